@@ -0,0 +1,52 @@
+package optcgo
+
+const (
+	DefBaggageMaxItems = 64
+	DefBaggageMaxBytes = 8192
+)
+
+// WithBaggageLimits limits the number of baggage items and the total bytes
+// of their keys and values carried by a span, items beyond the limits are
+// dropped. Non-positive values fall back to the defaults.
+func WithBaggageLimits(maxItems, maxBytes int) StartTracerOption {
+	return func(tracer *Tracer) {
+		tracer.baggageMaxItems = maxItems
+		tracer.baggageMaxBytes = maxBytes
+	}
+}
+
+func (tcr *Tracer) baggageLimits() (maxItems, maxBytes int) {
+	maxItems, maxBytes = DefBaggageMaxItems, DefBaggageMaxBytes
+	if tcr != nil {
+		if tcr.baggageMaxItems > 0 {
+			maxItems = tcr.baggageMaxItems
+		}
+		if tcr.baggageMaxBytes > 0 {
+			maxBytes = tcr.baggageMaxBytes
+		}
+	}
+
+	return
+}
+
+// setBaggageItem sets key:value in baggage if the result stays within the
+// limits and reports whether the item was set.
+func setBaggageItem(baggage map[string]string, key, value string, maxItems, maxBytes int) bool {
+	size := len(key) + len(value)
+	old, exists := baggage[key]
+	if !exists && len(baggage) >= maxItems {
+		return false
+	}
+	for k, v := range baggage {
+		size += len(k) + len(v)
+	}
+	if exists {
+		size -= len(key) + len(old)
+	}
+	if size > maxBytes {
+		return false
+	}
+	baggage[key] = value
+
+	return true
+}
@@ -0,0 +1,63 @@
+package optcgo
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/protobuf/proto"
+)
+
+// BinaryVersion is the first byte of SpanContext encoded in
+// opentracing.Binary format, it is followed by the big endian uint32 length
+// of the SpanContext protobuf message and the message itself.
+const BinaryVersion byte = 1
+
+// max size of SpanContext message accepted by extractBinary
+const maxBinarySize = 1 << 20
+
+func injectBinary(spctx *SpanContext, writer io.Writer) error {
+	bts, err := proto.Marshal(spctx)
+	if err != nil {
+		return err
+	}
+
+	buf := make([]byte, 5, 5+len(bts))
+	buf[0] = BinaryVersion
+	binary.BigEndian.PutUint32(buf[1:], uint32(len(bts)))
+	_, err = writer.Write(append(buf, bts...))
+
+	return err
+}
+
+func extractBinary(reader io.Reader) (*SpanContext, error) {
+	var head [5]byte
+	if n, err := io.ReadFull(reader, head[:]); err != nil {
+		if n == 0 && err == io.EOF {
+			return nil, opentracing.ErrSpanContextNotFound
+		}
+
+		return nil, opentracing.ErrSpanContextCorrupted
+	}
+	if head[0] != BinaryVersion {
+		return nil, opentracing.ErrSpanContextCorrupted
+	}
+	l := binary.BigEndian.Uint32(head[1:])
+	if l > maxBinarySize {
+		return nil, opentracing.ErrSpanContextCorrupted
+	}
+
+	bts := make([]byte, l)
+	if _, err := io.ReadFull(reader, bts); err != nil {
+		return nil, opentracing.ErrSpanContextCorrupted
+	}
+	spctx := &SpanContext{}
+	if err := proto.Unmarshal(bts, spctx); err != nil {
+		return nil, opentracing.ErrSpanContextCorrupted
+	}
+	if spctx.TraceIDHigh == 0 && spctx.TraceID == 0 {
+		return nil, opentracing.ErrSpanContextNotFound
+	}
+
+	return spctx, nil
+}
@@ -0,0 +1,29 @@
+package optcgo
+
+import (
+	"context"
+	"time"
+)
+
+// timeout of export requests sent by exporters
+const DefExporterTimeout = 10 * time.Second
+
+// Exporter sends batches of finished spans to a tracing backend.
+//
+// Export is called from the flush loop of Tracer, Shutdown is called once
+// when the Tracer is closed after the last batch has been exported.
+type Exporter interface {
+	Export(ctx context.Context, trace *Trace) error
+	Shutdown(ctx context.Context) error
+}
+
+// NoopExporter drops every trace, it is the default Exporter of Tracer.
+type NoopExporter struct{}
+
+func (NoopExporter) Export(ctx context.Context, trace *Trace) error {
+	return nil
+}
+
+func (NoopExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
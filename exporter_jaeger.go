@@ -0,0 +1,446 @@
+package optcgo
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	DefJaegerEndpoint = "http://localhost:14268"
+	jaegerTracesPath  = "/api/traces"
+)
+
+// globalTagsExporter is implemented by exporters reporting tracer global
+// tags apart from spans, NewTracer hands tags set by WithGlobalTags to them.
+type globalTagsExporter interface {
+	setGlobalTags(tags map[string]interface{})
+}
+
+type JaegerOption func(exporter *JaegerExporter)
+
+// WithJaegerProcessTags reports tags as Process tags in place of the global
+// tags of the Tracer.
+func WithJaegerProcessTags(tags map[string]interface{}) JaegerOption {
+	return func(exporter *JaegerExporter) {
+		exporter.setProcessTags(tags)
+	}
+}
+
+// WithJaegerHeaders adds headers, e.g. authorization tokens, to every
+// export request.
+func WithJaegerHeaders(headers map[string]string) JaegerOption {
+	return func(exporter *JaegerExporter) {
+		for k, v := range headers {
+			exporter.headers.Set(k, v)
+		}
+	}
+}
+
+func WithJaegerClient(client *http.Client) JaegerOption {
+	return func(exporter *JaegerExporter) {
+		exporter.client = client
+	}
+}
+
+var (
+	_ Exporter           = (*JaegerExporter)(nil)
+	_ globalTagsExporter = (*JaegerExporter)(nil)
+)
+
+// JaegerExporter posts traces as jaeger.thrift Batch, one per service, to
+// the /api/traces path of a Jaeger collector. Tracer global tags are
+// reported as Process tags instead of span tags.
+type JaegerExporter struct {
+	url      string
+	headers  http.Header
+	client   *http.Client
+	tags     map[string]interface{}
+	global   *Span
+	shutdown int32
+}
+
+// NewJaegerExporter creates exporter for the collector at endpoint, e.g.
+// http://localhost:14268.
+func NewJaegerExporter(endpoint string, opts ...JaegerOption) *JaegerExporter {
+	if endpoint == "" {
+		endpoint = DefJaegerEndpoint
+	}
+	exporter := &JaegerExporter{
+		url:     strings.TrimSuffix(endpoint, "/") + jaegerTracesPath,
+		headers: make(http.Header),
+		client:  &http.Client{Timeout: DefExporterTimeout},
+	}
+	for i := range opts {
+		opts[i](exporter)
+	}
+
+	return exporter
+}
+
+// setGlobalTags uses tags as Process tags unless they are set already, by
+// WithJaegerProcessTags or by the first Tracer sharing exp.
+func (exp *JaegerExporter) setGlobalTags(tags map[string]interface{}) {
+	if exp.global == nil && len(tags) != 0 {
+		exp.setProcessTags(tags)
+	}
+}
+
+func (exp *JaegerExporter) setProcessTags(tags map[string]interface{}) {
+	exp.tags = make(map[string]interface{}, len(tags))
+	for k, v := range tags {
+		exp.tags[k] = v
+	}
+	// process tags as they are set on every span
+	exp.global = &Span{}
+	exp.global.SetTags(exp.tags)
+}
+
+func (exp *JaegerExporter) Export(ctx context.Context, trace *Trace) error {
+	if atomic.LoadInt32(&exp.shutdown) != 0 {
+		return errExporterShutdown
+	}
+
+	var (
+		services []string
+		batches  = make(map[string][]*Span)
+	)
+	for _, sp := range trace.Trace {
+		if _, ok := batches[sp.Service]; !ok {
+			services = append(services, sp.Service)
+		}
+		batches[sp.Service] = append(batches[sp.Service], sp)
+	}
+	for _, service := range services {
+		if err := exp.send(ctx, exp.encodeBatch(service, batches[service])); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (exp *JaegerExporter) send(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, exp.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for k, v := range exp.headers {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/x-thrift")
+
+	resp, err := exp.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("export to jaeger collector failed with status: %s", resp.Status)
+	}
+
+	return nil
+}
+
+func (exp *JaegerExporter) Shutdown(ctx context.Context) error {
+	atomic.StoreInt32(&exp.shutdown, 1)
+	exp.client.CloseIdleConnections()
+
+	return nil
+}
+
+// thrift binary protocol types
+const (
+	thriftStop   byte = 0
+	thriftBool   byte = 2
+	thriftDouble byte = 4
+	thriftI32    byte = 8
+	thriftI64    byte = 10
+	thriftString byte = 11
+	thriftStruct byte = 12
+	thriftList   byte = 15
+)
+
+// jaeger.thrift TagType
+const (
+	jaegerTagString int32 = iota
+	jaegerTagDouble
+	jaegerTagBool
+	jaegerTagLong
+)
+
+type jaegerTag struct {
+	key     string
+	vType   int32
+	vStr    string
+	vDouble float64
+	vBool   bool
+	vLong   int64
+}
+
+// encodeBatch encodes spans of service as jaeger.thrift Batch.
+func (exp *JaegerExporter) encodeBatch(service string, spans []*Span) []byte {
+	w := &thriftWriter{}
+	// Batch.process
+	w.fieldBegin(thriftStruct, 1)
+	w.fieldBegin(thriftString, 1)
+	w.string(service)
+	if len(exp.tags) != 0 {
+		tags := make([]jaegerTag, 0, len(exp.tags))
+		for k, v := range exp.tags {
+			tags = append(tags, jaegerValueTag(k, v))
+		}
+		w.fieldBegin(thriftList, 2)
+		w.tags(tags)
+	}
+	w.fieldStop()
+	// Batch.spans
+	w.fieldBegin(thriftList, 2)
+	w.listBegin(thriftStruct, len(spans))
+	for _, sp := range spans {
+		w.span(sp, exp.spanTags(sp))
+	}
+	w.fieldStop()
+
+	return w.Bytes()
+}
+
+// spanTags converts span Meta and Metrics into typed tags, leaving out
+// tags already reported by Process.
+func (exp *JaegerExporter) spanTags(sp *Span) []jaegerTag {
+	tags := make([]jaegerTag, 0, len(sp.Meta)+len(sp.Metrics)+1)
+	for k, v := range sp.Meta {
+		if g, ok := exp.global.GetMeta()[k]; ok && g == v {
+			continue
+		}
+		tags = append(tags, jaegerTag{key: k, vType: jaegerTagString, vStr: v})
+	}
+	for k, v := range sp.Metrics {
+		if g, ok := exp.global.GetMetrics()[k]; ok && proto.Equal(g, v) {
+			continue
+		}
+		if tag, ok := jaegerNumericTag(k, v); ok {
+			tags = append(tags, tag)
+		}
+	}
+	switch sp.Status {
+	case SpanStatus_Error, SpanStatus_Crisis:
+		if _, ok := sp.tag(string(ext.Error)); !ok {
+			tags = append(tags, jaegerTag{key: string(ext.Error), vType: jaegerTagBool, vBool: true})
+		}
+	}
+
+	return tags
+}
+
+func jaegerValueTag(key string, value interface{}) jaegerTag {
+	switch v := value.(type) {
+	case string:
+		return jaegerTag{key: key, vType: jaegerTagString, vStr: v}
+	case bool:
+		return jaegerTag{key: key, vType: jaegerTagBool, vBool: v}
+	case int:
+		return jaegerTag{key: key, vType: jaegerTagLong, vLong: int64(v)}
+	case int32:
+		return jaegerTag{key: key, vType: jaegerTagLong, vLong: int64(v)}
+	case int64:
+		return jaegerTag{key: key, vType: jaegerTagLong, vLong: v}
+	case uint32:
+		return jaegerTag{key: key, vType: jaegerTagLong, vLong: int64(v)}
+	case float32:
+		return jaegerTag{key: key, vType: jaegerTagDouble, vDouble: float64(v)}
+	case float64:
+		return jaegerTag{key: key, vType: jaegerTagDouble, vDouble: v}
+	case *Numeric:
+		if tag, ok := jaegerNumericTag(key, v); ok {
+			return tag
+		}
+	}
+
+	return jaegerTag{key: key, vType: jaegerTagString, vStr: fmt.Sprintf("%v", value)}
+}
+
+func jaegerNumericTag(key string, number *Numeric) (jaegerTag, bool) {
+	switch v := number.GetNumeric().(type) {
+	case *Numeric_Int32Value:
+		return jaegerTag{key: key, vType: jaegerTagLong, vLong: int64(v.Int32Value)}, true
+	case *Numeric_Int64Value:
+		return jaegerTag{key: key, vType: jaegerTagLong, vLong: v.Int64Value}, true
+	case *Numeric_Uint32Value:
+		return jaegerTag{key: key, vType: jaegerTagLong, vLong: int64(v.Uint32Value)}, true
+	case *Numeric_Uint64Value:
+		if v.Uint64Value > math.MaxInt64 {
+			return jaegerTag{key: key, vType: jaegerTagDouble, vDouble: float64(v.Uint64Value)}, true
+		}
+
+		return jaegerTag{key: key, vType: jaegerTagLong, vLong: int64(v.Uint64Value)}, true
+	case *Numeric_Floatvalue:
+		return jaegerTag{key: key, vType: jaegerTagDouble, vDouble: float64(v.Floatvalue)}, true
+	case *Numeric_Doublevalue:
+		return jaegerTag{key: key, vType: jaegerTagDouble, vDouble: v.Doublevalue}, true
+	case *Numeric_Boolvalue:
+		return jaegerTag{key: key, vType: jaegerTagBool, vBool: v.Boolvalue}, true
+	default:
+		return jaegerTag{}, false
+	}
+}
+
+func jaegerLogTag(f *LogField) (jaegerTag, bool) {
+	switch v := f.Value.(type) {
+	case *LogField_Stringvalue:
+		return jaegerTag{key: f.Key, vType: jaegerTagString, vStr: v.Stringvalue}, true
+	case *LogField_Boolvalue:
+		return jaegerTag{key: f.Key, vType: jaegerTagBool, vBool: v.Boolvalue}, true
+	case *LogField_Int64Value:
+		return jaegerTag{key: f.Key, vType: jaegerTagLong, vLong: v.Int64Value}, true
+	case *LogField_Uint64Value:
+		return jaegerNumericTag(f.Key, &Numeric{Numeric: &Numeric_Uint64Value{Uint64Value: v.Uint64Value}})
+	case *LogField_Doublevalue:
+		return jaegerTag{key: f.Key, vType: jaegerTagDouble, vDouble: v.Doublevalue}, true
+	case *LogField_Errorvalue:
+		return jaegerTag{key: f.Key, vType: jaegerTagString, vStr: v.Errorvalue}, true
+	case *LogField_Objectvalue:
+		return jaegerTag{key: f.Key, vType: jaegerTagString, vStr: v.Objectvalue}, true
+	default:
+		return jaegerTag{}, false
+	}
+}
+
+// thriftWriter writes thrift binary protocol.
+type thriftWriter struct {
+	bytes.Buffer
+}
+
+func (w *thriftWriter) fieldBegin(typ byte, id int16) {
+	w.WriteByte(typ)
+	w.i16(id)
+}
+
+func (w *thriftWriter) fieldStop() {
+	w.WriteByte(thriftStop)
+}
+
+func (w *thriftWriter) listBegin(typ byte, size int) {
+	w.WriteByte(typ)
+	w.i32(int32(size))
+}
+
+func (w *thriftWriter) i16(v int16) {
+	var bts [2]byte
+	binary.BigEndian.PutUint16(bts[:], uint16(v))
+	w.Write(bts[:])
+}
+
+func (w *thriftWriter) i32(v int32) {
+	var bts [4]byte
+	binary.BigEndian.PutUint32(bts[:], uint32(v))
+	w.Write(bts[:])
+}
+
+func (w *thriftWriter) i64(v int64) {
+	var bts [8]byte
+	binary.BigEndian.PutUint64(bts[:], uint64(v))
+	w.Write(bts[:])
+}
+
+func (w *thriftWriter) string(s string) {
+	w.i32(int32(len(s)))
+	w.WriteString(s)
+}
+
+func (w *thriftWriter) tags(tags []jaegerTag) {
+	w.listBegin(thriftStruct, len(tags))
+	for _, tag := range tags {
+		w.fieldBegin(thriftString, 1)
+		w.string(tag.key)
+		w.fieldBegin(thriftI32, 2)
+		w.i32(tag.vType)
+		switch tag.vType {
+		case jaegerTagString:
+			w.fieldBegin(thriftString, 3)
+			w.string(tag.vStr)
+		case jaegerTagDouble:
+			w.fieldBegin(thriftDouble, 4)
+			w.i64(int64(math.Float64bits(tag.vDouble)))
+		case jaegerTagBool:
+			w.fieldBegin(thriftBool, 5)
+			if tag.vBool {
+				w.WriteByte(1)
+			} else {
+				w.WriteByte(0)
+			}
+		case jaegerTagLong:
+			w.fieldBegin(thriftI64, 6)
+			w.i64(tag.vLong)
+		}
+		w.fieldStop()
+	}
+}
+
+// span writes jaeger.thrift Span, timestamps are in microseconds.
+func (w *thriftWriter) span(sp *Span, tags []jaegerTag) {
+	w.fieldBegin(thriftI64, 1)
+	w.i64(sp.TraceID)
+	w.fieldBegin(thriftI64, 2)
+	w.i64(sp.TraceIDHigh)
+	w.fieldBegin(thriftI64, 3)
+	w.i64(sp.SpanID)
+	w.fieldBegin(thriftI64, 4)
+	w.i64(sp.ParentID)
+	w.fieldBegin(thriftString, 5)
+	w.string(sp.Operation)
+	if len(sp.Links) != 0 {
+		w.fieldBegin(thriftList, 6)
+		w.listBegin(thriftStruct, len(sp.Links))
+		for _, l := range sp.Links {
+			w.fieldBegin(thriftI32, 1)
+			w.i32(int32(l.Type))
+			w.fieldBegin(thriftI64, 2)
+			w.i64(l.TraceID)
+			w.fieldBegin(thriftI64, 3)
+			w.i64(l.TraceIDHigh)
+			w.fieldBegin(thriftI64, 4)
+			w.i64(l.SpanID)
+			w.fieldStop()
+		}
+	}
+	w.fieldBegin(thriftI32, 7)
+	w.i32(jaegerFlagSampled)
+	w.fieldBegin(thriftI64, 8)
+	w.i64(sp.StartTime / 1e3)
+	w.fieldBegin(thriftI64, 9)
+	w.i64((sp.EndTime - sp.StartTime) / 1e3)
+	if len(tags) != 0 {
+		w.fieldBegin(thriftList, 10)
+		w.tags(tags)
+	}
+	if len(sp.Logs) != 0 {
+		w.fieldBegin(thriftList, 11)
+		w.listBegin(thriftStruct, len(sp.Logs))
+		for _, l := range sp.Logs {
+			fields := make([]jaegerTag, 0, len(l.Fields))
+			for _, f := range l.Fields {
+				if tag, ok := jaegerLogTag(f); ok {
+					fields = append(fields, tag)
+				}
+			}
+			w.fieldBegin(thriftI64, 1)
+			w.i64(l.Timestamp / 1e3)
+			w.fieldBegin(thriftList, 2)
+			w.tags(fields)
+			w.fieldStop()
+		}
+	}
+	w.fieldStop()
+}
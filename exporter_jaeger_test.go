@@ -0,0 +1,216 @@
+package optcgo
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/opentracing/opentracing-go/ext"
+)
+
+// readThrift decodes thrift binary struct into values keyed by field id.
+func readThrift(t *testing.T, r *bytes.Reader) map[int16]interface{} {
+	t.Helper()
+
+	fields := make(map[int16]interface{})
+	for {
+		typ, err := r.ReadByte()
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if typ == thriftStop {
+			return fields
+		}
+		var id int16
+		binary.Read(r, binary.BigEndian, &id)
+		fields[id] = readThriftValue(t, r, typ)
+	}
+}
+
+func readThriftValue(t *testing.T, r *bytes.Reader, typ byte) interface{} {
+	switch typ {
+	case thriftBool:
+		b, _ := r.ReadByte()
+
+		return b == 1
+	case thriftDouble:
+		var v uint64
+		binary.Read(r, binary.BigEndian, &v)
+
+		return math.Float64frombits(v)
+	case thriftI32:
+		var v int32
+		binary.Read(r, binary.BigEndian, &v)
+
+		return v
+	case thriftI64:
+		var v int64
+		binary.Read(r, binary.BigEndian, &v)
+
+		return v
+	case thriftString:
+		var l int32
+		binary.Read(r, binary.BigEndian, &l)
+		bts := make([]byte, l)
+		io.ReadFull(r, bts)
+
+		return string(bts)
+	case thriftStruct:
+		return readThrift(t, r)
+	case thriftList:
+		elem, _ := r.ReadByte()
+		var size int32
+		binary.Read(r, binary.BigEndian, &size)
+		list := make([]interface{}, size)
+		for i := range list {
+			list[i] = readThriftValue(t, r, elem)
+		}
+
+		return list
+	default:
+		t.Fatalf("unexpected thrift type %d", typ)
+
+		return nil
+	}
+}
+
+func thriftTags(list interface{}) map[string]interface{} {
+	tags := make(map[string]interface{})
+	items, _ := list.([]interface{})
+	for _, item := range items {
+		tag := item.(map[int16]interface{})
+		for id := int16(3); id <= 6; id++ {
+			if v, ok := tag[id]; ok {
+				tags[tag[1].(string)] = v
+			}
+		}
+	}
+
+	return tags
+}
+
+// thriftTagTypes maps tag keys to their TagType, 0 STRING, 1 DOUBLE, 2 BOOL
+// and 3 LONG.
+func thriftTagTypes(list interface{}) map[string]int32 {
+	types := make(map[string]int32)
+	items, _ := list.([]interface{})
+	for _, item := range items {
+		tag := item.(map[int16]interface{})
+		types[tag[1].(string)] = tag[2].(int32)
+	}
+
+	return types
+}
+
+func TestJaegerExporter(t *testing.T) {
+	var batch map[int16]interface{}
+	svr := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/api/traces" || req.Header.Get("Content-Type") != "application/x-thrift" {
+			t.Errorf("unexpected request %s %s", req.URL.Path, req.Header.Get("Content-Type"))
+		}
+		bts, _ := io.ReadAll(req.Body)
+		batch = readThrift(t, bytes.NewReader(bts))
+		resp.WriteHeader(http.StatusAccepted)
+	}))
+	defer svr.Close()
+
+	processTags := map[string]interface{}{"env": "prod", "canary": true}
+	exporter := NewJaegerExporter(svr.URL, WithJaegerProcessTags(processTags))
+	// tags are copied
+	processTags["env"] = "dev"
+
+	trace := testOTLPTrace()
+	trace.Trace[0].StartTime = 1_000_000
+	trace.Trace[0].EndTime = 3_500_000
+	trace.Trace[0].Logs[0].Timestamp = 2_000_000
+	trace.Trace[0].Meta["env"] = "prod"
+	trace.Trace[0].SetTag("ratio", 0.5)
+	if err := exporter.Export(context.Background(), trace); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	process := batch[1].(map[int16]interface{})
+	if process[1] != "checkout" {
+		t.Errorf("unexpected service name %v", process[1])
+	}
+	if tags := thriftTags(process[2]); tags["env"] != "prod" || tags["canary"] != true {
+		t.Errorf("unexpected process tags %v", tags)
+	}
+
+	spans := batch[2].([]interface{})
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	span := spans[0].(map[int16]interface{})
+	if span[1] != int64(2) || span[2] != int64(1) || span[3] != int64(3) || span[4] != int64(4) || span[5] != "POST /orders" {
+		t.Errorf("unexpected span %v", span)
+	}
+	if span[7] != int32(1) || span[8] != int64(1000) || span[9] != int64(2500) {
+		t.Errorf("unexpected flags %v, start %v or duration %v", span[7], span[8], span[9])
+	}
+	tags := thriftTags(span[10])
+	if tags["http.method"] != "POST" || tags["http.status_code"] != int64(500) || tags["error"] != true {
+		t.Errorf("unexpected span tags %v", tags)
+	}
+	if tags[string(ext.SpanKind)] != "server" || tags["ratio"] != 0.5 {
+		t.Errorf("unexpected span tags %v", tags)
+	}
+	types := thriftTagTypes(span[10])
+	if types[string(ext.SpanKind)] != 0 || types["ratio"] != 1 || types["error"] != 2 || types["http.status_code"] != 3 {
+		t.Errorf("unexpected span tag types %v", types)
+	}
+	if _, ok := tags["env"]; ok {
+		t.Error("global tag should be reported by process only")
+	}
+	refs := span[6].([]interface{})
+	if ref := refs[0].(map[int16]interface{}); ref[1] != int32(1) || ref[2] != int64(5) || ref[4] != int64(6) {
+		t.Errorf("unexpected references %v", refs)
+	}
+	logs := span[11].([]interface{})
+	l := logs[0].(map[int16]interface{})
+	if fields := thriftTags(l[2]); l[1] != int64(2000) || fields["event"] != "retry" || fields["attempt"] != int64(2) {
+		t.Errorf("unexpected log %v", l)
+	}
+}
+
+func TestJaegerExporterGlobalTags(t *testing.T) {
+	var batch map[int16]interface{}
+	svr := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		bts, _ := io.ReadAll(req.Body)
+		batch = readThrift(t, bytes.NewReader(bts))
+		resp.WriteHeader(http.StatusAccepted)
+	}))
+	defer svr.Close()
+
+	for _, override := range []bool{false, true} {
+		var opts []JaegerOption
+		if override {
+			opts = append(opts, WithJaegerProcessTags(map[string]interface{}{"env": "staging"}))
+		}
+		exporter := NewJaegerExporter(svr.URL, opts...)
+		tracer := NewTracer("checkout", WithExporter(exporter), WithGlobalTags(map[string]interface{}{"env": "prod", "shard": 3}))
+		tracer.StartSpan("root").Finish()
+		tracer.Close()
+
+		process := batch[1].(map[int16]interface{})
+		span := batch[2].([]interface{})[0].(map[int16]interface{})
+		processTags, spanTags := thriftTags(process[2]), thriftTags(span[10])
+		if override {
+			if processTags["env"] != "staging" || spanTags["env"] != "prod" || spanTags["shard"] != int64(3) {
+				t.Errorf("unexpected process tags %v or span tags %v", processTags, spanTags)
+			}
+			continue
+		}
+		if processTags["env"] != "prod" || processTags["shard"] != int64(3) {
+			t.Errorf("unexpected process tags %v", processTags)
+		}
+		if _, ok := spanTags["env"]; ok {
+			t.Errorf("global tags reported on span %v", spanTags)
+		}
+	}
+}
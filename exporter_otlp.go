@@ -0,0 +1,203 @@
+package optcgo
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"time"
+
+	"github.com/opentracing/opentracing-go/ext"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+// instrumentation scope of spans exported in OTLP
+const otlpScopeName = "github.com/CodapeWild/universal-opentracing-transformer"
+
+// traceToOTLP converts trace into OTLP ResourceSpans, one per service.
+func traceToOTLP(trace *Trace) []*tracepb.ResourceSpans {
+	var (
+		rss      []*tracepb.ResourceSpans
+		services = make(map[string]*tracepb.ScopeSpans)
+	)
+	for _, sp := range trace.Trace {
+		ss, ok := services[sp.Service]
+		if !ok {
+			ss = &tracepb.ScopeSpans{Scope: &commonpb.InstrumentationScope{Name: otlpScopeName}}
+			services[sp.Service] = ss
+			rss = append(rss, &tracepb.ResourceSpans{
+				Resource: &resourcepb.Resource{
+					Attributes: []*commonpb.KeyValue{otlpString("service.name", sp.Service)},
+				},
+				ScopeSpans: []*tracepb.ScopeSpans{ss},
+			})
+		}
+		ss.Spans = append(ss.Spans, spanToOTLP(sp))
+	}
+
+	return rss
+}
+
+func spanToOTLP(sp *Span) *tracepb.Span {
+	span := &tracepb.Span{
+		TraceId:           otlpTraceID(sp.TraceIDHigh, sp.TraceID),
+		SpanId:            otlpSpanID(sp.SpanID),
+		TraceState:        sp.TraceState,
+		Name:              sp.Operation,
+		Kind:              otlpSpanKind(sp.Meta[string(ext.SpanKind)]),
+		StartTimeUnixNano: uint64(sp.StartTime),
+		EndTimeUnixNano:   uint64(sp.EndTime),
+		Status:            &tracepb.Status{},
+	}
+	if sp.ParentID != 0 {
+		span.ParentSpanId = otlpSpanID(sp.ParentID)
+	}
+	for k, v := range sp.Meta {
+		span.Attributes = append(span.Attributes, otlpString(k, v))
+	}
+	for k, v := range sp.Metrics {
+		if kv := otlpNumeric(k, v); kv != nil {
+			span.Attributes = append(span.Attributes, kv)
+		}
+	}
+	switch sp.Status {
+	case SpanStatus_Error, SpanStatus_Crisis:
+		span.Status.Code = tracepb.Status_STATUS_CODE_ERROR
+		span.Status.Message = sp.Status.String()
+	}
+	for _, l := range sp.Logs {
+		span.Events = append(span.Events, logToOTLP(l))
+	}
+	for _, l := range sp.Links {
+		refType := "child_of"
+		if l.Type == SpanReferenceType_FollowsFrom {
+			refType = "follows_from"
+		}
+		span.Links = append(span.Links, &tracepb.Span_Link{
+			TraceId:    otlpTraceID(l.TraceIDHigh, l.TraceID),
+			SpanId:     otlpSpanID(l.SpanID),
+			Attributes: []*commonpb.KeyValue{otlpString("opentracing.ref_type", refType)},
+		})
+	}
+
+	return span
+}
+
+// logToOTLP converts span log into event named after its event field.
+func logToOTLP(l *SpanLog) *tracepb.Span_Event {
+	event := &tracepb.Span_Event{TimeUnixNano: uint64(l.Timestamp), Name: "log"}
+	for _, f := range l.Fields {
+		var value *commonpb.AnyValue
+		switch v := f.Value.(type) {
+		case *LogField_Stringvalue:
+			if f.Key == "event" {
+				event.Name = v.Stringvalue
+				continue
+			}
+			value = &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: v.Stringvalue}}
+		case *LogField_Boolvalue:
+			value = &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: v.Boolvalue}}
+		case *LogField_Int64Value:
+			value = &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: v.Int64Value}}
+		case *LogField_Uint64Value:
+			value = otlpUint64(v.Uint64Value)
+		case *LogField_Doublevalue:
+			value = &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: v.Doublevalue}}
+		case *LogField_Errorvalue:
+			value = &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: v.Errorvalue}}
+		case *LogField_Objectvalue:
+			value = &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: v.Objectvalue}}
+		default:
+			continue
+		}
+		event.Attributes = append(event.Attributes, &commonpb.KeyValue{Key: f.Key, Value: value})
+	}
+
+	return event
+}
+
+func otlpTraceID(high, low int64) []byte {
+	id := make([]byte, 16)
+	binary.BigEndian.PutUint64(id[:8], uint64(high))
+	binary.BigEndian.PutUint64(id[8:], uint64(low))
+
+	return id
+}
+
+func otlpSpanID(id int64) []byte {
+	bts := make([]byte, 8)
+	binary.BigEndian.PutUint64(bts, uint64(id))
+
+	return bts
+}
+
+func otlpSpanKind(kind string) tracepb.Span_SpanKind {
+	switch ext.SpanKindEnum(kind) {
+	case ext.SpanKindRPCServerEnum:
+		return tracepb.Span_SPAN_KIND_SERVER
+	case ext.SpanKindRPCClientEnum:
+		return tracepb.Span_SPAN_KIND_CLIENT
+	case ext.SpanKindProducerEnum:
+		return tracepb.Span_SPAN_KIND_PRODUCER
+	case ext.SpanKindConsumerEnum:
+		return tracepb.Span_SPAN_KIND_CONSUMER
+	default:
+		return tracepb.Span_SPAN_KIND_INTERNAL
+	}
+}
+
+func otlpString(key, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{Key: key, Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}}}
+}
+
+func otlpNumeric(key string, number *Numeric) *commonpb.KeyValue {
+	var value *commonpb.AnyValue
+	switch v := number.GetNumeric().(type) {
+	case *Numeric_Int32Value:
+		value = &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: int64(v.Int32Value)}}
+	case *Numeric_Int64Value:
+		value = &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: v.Int64Value}}
+	case *Numeric_Uint32Value:
+		value = &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: int64(v.Uint32Value)}}
+	case *Numeric_Uint64Value:
+		value = otlpUint64(v.Uint64Value)
+	case *Numeric_Floatvalue:
+		value = &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: float64(v.Floatvalue)}}
+	case *Numeric_Doublevalue:
+		value = &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: v.Doublevalue}}
+	case *Numeric_Boolvalue:
+		value = &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: v.Boolvalue}}
+	default:
+		return nil
+	}
+
+	return &commonpb.KeyValue{Key: key, Value: value}
+}
+
+// otlpUint64 converts uint64 into int value, or into double value if it
+// overflows int64.
+func otlpUint64(v uint64) *commonpb.AnyValue {
+	if v > math.MaxInt64 {
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: float64(v)}}
+	}
+
+	return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: int64(v)}}
+}
+
+// waitRetry waits before the next attempt of a failed export, it returns an
+// error without waiting if the wait would pass the deadline of ctx.
+func waitRetry(ctx context.Context, wait time.Duration) error {
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+		return context.DeadlineExceeded
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
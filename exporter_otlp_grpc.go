@@ -0,0 +1,168 @@
+package optcgo
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const DefOTLPGRPCEndpoint = "localhost:4317"
+
+type OTLPGRPCOption func(exporter *OTLPGRPCExporter)
+
+// WithOTLPGRPCInsecure disables transport security, TLS with the system
+// roots is used by default.
+func WithOTLPGRPCInsecure() OTLPGRPCOption {
+	return func(exporter *OTLPGRPCExporter) {
+		exporter.creds = insecure.NewCredentials()
+	}
+}
+
+func WithOTLPGRPCTLS(config *tls.Config) OTLPGRPCOption {
+	return func(exporter *OTLPGRPCExporter) {
+		exporter.creds = credentials.NewTLS(config)
+	}
+}
+
+// WithOTLPGRPCHeaders adds metadata, e.g. authorization tokens, to every
+// export call.
+func WithOTLPGRPCHeaders(headers map[string]string) OTLPGRPCOption {
+	return func(exporter *OTLPGRPCExporter) {
+		for k, v := range headers {
+			exporter.headers.Set(k, v)
+		}
+	}
+}
+
+// WithOTLPGRPCTimeout sets the timeout of each export call.
+func WithOTLPGRPCTimeout(d time.Duration) OTLPGRPCOption {
+	return func(exporter *OTLPGRPCExporter) {
+		exporter.timeout = d
+	}
+}
+
+// WithOTLPGRPCRetry sets the maximum retries of a failed export and the
+// initial backoff doubled after each retry.
+func WithOTLPGRPCRetry(maxRetries int, backoff time.Duration) OTLPGRPCOption {
+	return func(exporter *OTLPGRPCExporter) {
+		exporter.maxRetries = maxRetries
+		exporter.backoff = backoff
+	}
+}
+
+// WithOTLPGRPCDialOptions appends options used to dial the collector.
+func WithOTLPGRPCDialOptions(opts ...grpc.DialOption) OTLPGRPCOption {
+	return func(exporter *OTLPGRPCExporter) {
+		exporter.dialOpts = append(exporter.dialOpts, opts...)
+	}
+}
+
+var _ Exporter = (*OTLPGRPCExporter)(nil)
+
+// OTLPGRPCExporter sends traces to the TraceService of an OpenTelemetry
+// collector over gRPC. Calls failed with UNAVAILABLE or RESOURCE_EXHAUSTED
+// are retried with exponential backoff for at most DefOTLPMaxElapsed.
+type OTLPGRPCExporter struct {
+	creds      credentials.TransportCredentials
+	headers    metadata.MD
+	timeout    time.Duration
+	maxRetries int
+	backoff    time.Duration
+	dialOpts   []grpc.DialOption
+	conn       *grpc.ClientConn
+	client     coltracepb.TraceServiceClient
+	shutdown   int32
+}
+
+// NewOTLPGRPCExporter creates exporter for the collector at endpoint, e.g.
+// localhost:4317. The connection is established in background.
+func NewOTLPGRPCExporter(endpoint string, opts ...OTLPGRPCOption) (*OTLPGRPCExporter, error) {
+	if endpoint == "" {
+		endpoint = DefOTLPGRPCEndpoint
+	}
+	exporter := &OTLPGRPCExporter{
+		creds:      credentials.NewTLS(&tls.Config{}),
+		headers:    metadata.MD{},
+		timeout:    DefOTLPTimeout,
+		maxRetries: DefOTLPMaxRetries,
+		backoff:    DefOTLPBackoff,
+	}
+	for i := range opts {
+		opts[i](exporter)
+	}
+
+	conn, err := grpc.Dial(endpoint, append([]grpc.DialOption{grpc.WithTransportCredentials(exporter.creds)}, exporter.dialOpts...)...)
+	if err != nil {
+		return nil, err
+	}
+	exporter.conn = conn
+	exporter.client = coltracepb.NewTraceServiceClient(conn)
+
+	return exporter, nil
+}
+
+func (exp *OTLPGRPCExporter) Export(ctx context.Context, trace *Trace) error {
+	if atomic.LoadInt32(&exp.shutdown) != 0 {
+		return errExporterShutdown
+	}
+	if len(trace.Trace) == 0 {
+		return nil
+	}
+
+	req := &coltracepb.ExportTraceServiceRequest{ResourceSpans: traceToOTLP(trace)}
+	if len(exp.headers) != 0 {
+		ctx = metadata.NewOutgoingContext(ctx, exp.headers)
+	}
+	ctx, cancel := context.WithTimeout(ctx, DefOTLPMaxElapsed)
+	defer cancel()
+	backoff := exp.backoff
+	for retry := 0; ; retry++ {
+		err := exp.export(ctx, req)
+		if err == nil || retry >= exp.maxRetries {
+			return err
+		}
+		switch status.Code(err) {
+		case codes.Unavailable, codes.ResourceExhausted:
+		default:
+			return err
+		}
+
+		if waitRetry(ctx, backoff) != nil {
+			return err
+		}
+		backoff *= 2
+	}
+}
+
+func (exp *OTLPGRPCExporter) export(ctx context.Context, req *coltracepb.ExportTraceServiceRequest) error {
+	ctx, cancel := context.WithTimeout(ctx, exp.timeout)
+	defer cancel()
+
+	resp, err := exp.client.Export(ctx, req)
+	if err != nil {
+		return err
+	}
+	if ps := resp.GetPartialSuccess(); ps.GetRejectedSpans() > 0 {
+		return fmt.Errorf("otlp collector rejected %d spans: %s", ps.GetRejectedSpans(), ps.GetErrorMessage())
+	}
+
+	return nil
+}
+
+func (exp *OTLPGRPCExporter) Shutdown(ctx context.Context) error {
+	if !atomic.CompareAndSwapInt32(&exp.shutdown, 0, 1) {
+		return nil
+	}
+
+	return exp.conn.Close()
+}
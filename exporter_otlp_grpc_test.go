@@ -0,0 +1,115 @@
+package optcgo
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type testTraceService struct {
+	coltracepb.UnimplementedTraceServiceServer
+	t     *testing.T
+	calls int32
+	code  codes.Code
+}
+
+func (svc *testTraceService) Export(ctx context.Context, req *coltracepb.ExportTraceServiceRequest) (*coltracepb.ExportTraceServiceResponse, error) {
+	if atomic.AddInt32(&svc.calls, 1) == 1 {
+		return nil, status.Error(svc.code, "try later")
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	if auth := md.Get("authorization"); len(auth) != 1 || auth[0] != "Bearer token" {
+		svc.t.Errorf("missing authorization metadata, got %v", auth)
+	}
+	checkOTLPRequest(svc.t, req)
+
+	return &coltracepb.ExportTraceServiceResponse{}, nil
+}
+
+func startTestTraceService(t *testing.T, svc *testTraceService) grpc.DialOption {
+	lis := bufconn.Listen(1 << 20)
+	svr := grpc.NewServer()
+	coltracepb.RegisterTraceServiceServer(svr, svc)
+	go svr.Serve(lis)
+	t.Cleanup(svr.Stop)
+
+	return grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return lis.DialContext(ctx)
+	})
+}
+
+func TestOTLPGRPCExporter(t *testing.T) {
+	svc := &testTraceService{t: t, code: codes.Unavailable}
+	exporter, err := NewOTLPGRPCExporter("bufnet",
+		WithOTLPGRPCInsecure(),
+		WithOTLPGRPCDialOptions(startTestTraceService(t, svc)),
+		WithOTLPGRPCHeaders(map[string]string{"Authorization": "Bearer token"}),
+		WithOTLPGRPCRetry(2, time.Millisecond),
+	)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if err = exporter.Export(context.Background(), testOTLPTrace()); err != nil {
+		t.Fatal(err.Error())
+	}
+	if n := atomic.LoadInt32(&svc.calls); n != 2 {
+		t.Errorf("expected 1 retry, got %d calls", n)
+	}
+
+	exporter.Shutdown(context.Background())
+	if err = exporter.Export(context.Background(), testOTLPTrace()); err == nil {
+		t.Error("expected error after shutdown")
+	}
+}
+
+func TestOTLPGRPCExporterNoRetry(t *testing.T) {
+	svc := &testTraceService{t: t, code: codes.InvalidArgument}
+	exporter, err := NewOTLPGRPCExporter("bufnet",
+		WithOTLPGRPCInsecure(),
+		WithOTLPGRPCDialOptions(startTestTraceService(t, svc)),
+		WithOTLPGRPCRetry(2, time.Millisecond),
+	)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer exporter.Shutdown(context.Background())
+
+	if err = exporter.Export(context.Background(), testOTLPTrace()); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument, got %v", err)
+	}
+	if n := atomic.LoadInt32(&svc.calls); n != 1 {
+		t.Errorf("expected no retry, got %d calls", n)
+	}
+}
+
+func TestOTLPGRPCExporterRetryDeadline(t *testing.T) {
+	svc := &testTraceService{t: t, code: codes.Unavailable}
+	exporter, err := NewOTLPGRPCExporter("bufnet",
+		WithOTLPGRPCInsecure(),
+		WithOTLPGRPCDialOptions(startTestTraceService(t, svc)),
+		WithOTLPGRPCRetry(2, time.Hour),
+	)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer exporter.Shutdown(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	start := time.Now()
+	if err = exporter.Export(ctx, testOTLPTrace()); status.Code(err) != codes.Unavailable {
+		t.Errorf("expected Unavailable, got %v", err)
+	}
+	if d := time.Since(start); d > 500*time.Millisecond {
+		t.Errorf("export waited %s for a retry past its deadline", d)
+	}
+}
@@ -0,0 +1,202 @@
+package optcgo
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	DefOTLPHTTPEndpoint = "http://localhost:4318"
+	DefOTLPTimeout      = 10 * time.Second
+	DefOTLPMaxRetries   = 5
+	DefOTLPBackoff      = time.Second
+	DefOTLPMaxElapsed   = 30 * time.Second
+	otlpTracesPath      = "/v1/traces"
+)
+
+var errExporterShutdown = errors.New("exporter is shut down")
+
+type OTLPHTTPOption func(exporter *OTLPHTTPExporter)
+
+// WithOTLPHTTPHeaders adds headers, e.g. authorization tokens, to every
+// export request.
+func WithOTLPHTTPHeaders(headers map[string]string) OTLPHTTPOption {
+	return func(exporter *OTLPHTTPExporter) {
+		for k, v := range headers {
+			exporter.headers.Set(k, v)
+		}
+	}
+}
+
+// WithOTLPHTTPCompression enables or disables gzip compression of request
+// bodies, compression is enabled by default.
+func WithOTLPHTTPCompression(enable bool) OTLPHTTPOption {
+	return func(exporter *OTLPHTTPExporter) {
+		exporter.gzip = enable
+	}
+}
+
+// WithOTLPHTTPRetry sets the maximum retries of a failed export and the
+// initial backoff doubled after each retry.
+func WithOTLPHTTPRetry(maxRetries int, backoff time.Duration) OTLPHTTPOption {
+	return func(exporter *OTLPHTTPExporter) {
+		exporter.maxRetries = maxRetries
+		exporter.backoff = backoff
+	}
+}
+
+func WithOTLPHTTPClient(client *http.Client) OTLPHTTPOption {
+	return func(exporter *OTLPHTTPExporter) {
+		exporter.client = client
+	}
+}
+
+var _ Exporter = (*OTLPHTTPExporter)(nil)
+
+// OTLPHTTPExporter posts traces as OTLP ExportTraceServiceRequest protobuf
+// to the /v1/traces path of an OpenTelemetry collector. Requests failed
+// with network errors or status 429, 502, 503 and 504 are retried with
+// exponential backoff, honoring Retry-After, for at most DefOTLPMaxElapsed.
+type OTLPHTTPExporter struct {
+	url        string
+	headers    http.Header
+	client     *http.Client
+	gzip       bool
+	maxRetries int
+	backoff    time.Duration
+	shutdown   int32
+}
+
+// NewOTLPHTTPExporter creates exporter for the collector at endpoint, e.g.
+// http://localhost:4318.
+func NewOTLPHTTPExporter(endpoint string, opts ...OTLPHTTPOption) *OTLPHTTPExporter {
+	if endpoint == "" {
+		endpoint = DefOTLPHTTPEndpoint
+	}
+	exporter := &OTLPHTTPExporter{
+		url:        strings.TrimSuffix(endpoint, "/") + otlpTracesPath,
+		headers:    make(http.Header),
+		client:     &http.Client{Timeout: DefOTLPTimeout},
+		gzip:       true,
+		maxRetries: DefOTLPMaxRetries,
+		backoff:    DefOTLPBackoff,
+	}
+	for i := range opts {
+		opts[i](exporter)
+	}
+
+	return exporter
+}
+
+func (exp *OTLPHTTPExporter) Export(ctx context.Context, trace *Trace) error {
+	if atomic.LoadInt32(&exp.shutdown) != 0 {
+		return errExporterShutdown
+	}
+	if len(trace.Trace) == 0 {
+		return nil
+	}
+
+	body, err := proto.Marshal(&coltracepb.ExportTraceServiceRequest{ResourceSpans: traceToOTLP(trace)})
+	if err != nil {
+		return err
+	}
+	if exp.gzip {
+		buf := &bytes.Buffer{}
+		zw := gzip.NewWriter(buf)
+		if _, err = zw.Write(body); err != nil {
+			return err
+		}
+		if err = zw.Close(); err != nil {
+			return err
+		}
+		body = buf.Bytes()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, DefOTLPMaxElapsed)
+	defer cancel()
+	backoff := exp.backoff
+	for retry := 0; ; retry++ {
+		wait, err := exp.send(ctx, body)
+		if err == nil || wait < 0 || retry >= exp.maxRetries {
+			return err
+		}
+		if wait == 0 {
+			wait = backoff
+			backoff *= 2
+		}
+		if waitRetry(ctx, wait) != nil {
+			return err
+		}
+	}
+}
+
+// send posts body once, it returns a negative wait if the error is not
+// retryable, or the wait required by the collector if any.
+func (exp *OTLPHTTPExporter) send(ctx context.Context, body []byte) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, exp.url, bytes.NewReader(body))
+	if err != nil {
+		return -1, err
+	}
+	for k, v := range exp.headers {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	if exp.gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+
+	resp, err := exp.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return -1, err
+		}
+
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	bts, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		result := &coltracepb.ExportTraceServiceResponse{}
+		if len(bts) != 0 && proto.Unmarshal(bts, result) == nil {
+			if ps := result.GetPartialSuccess(); ps.GetRejectedSpans() > 0 {
+				return -1, fmt.Errorf("otlp collector rejected %d spans: %s", ps.GetRejectedSpans(), ps.GetErrorMessage())
+			}
+		}
+
+		return 0, nil
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		var wait time.Duration
+		if s, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && s > 0 {
+			wait = time.Duration(s) * time.Second
+		}
+
+		return wait, fmt.Errorf("export to otlp collector failed with status: %s", resp.Status)
+	default:
+		return -1, fmt.Errorf("export to otlp collector failed with status: %s", resp.Status)
+	}
+}
+
+func (exp *OTLPHTTPExporter) Shutdown(ctx context.Context) error {
+	atomic.StoreInt32(&exp.shutdown, 1)
+	exp.client.CloseIdleConnections()
+
+	return nil
+}
@@ -0,0 +1,175 @@
+package optcgo
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/opentracing/opentracing-go/ext"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func testOTLPTrace() *Trace {
+	sp := &Span{
+		TraceIDHigh: 1,
+		TraceID:     2,
+		SpanID:      3,
+		ParentID:    4,
+		Service:     "checkout",
+		Operation:   "POST /orders",
+		StartTime:   1000,
+		EndTime:     2000,
+		Logs: []*SpanLog{{Timestamp: 1500, Fields: []*LogField{
+			{Key: "event", Value: &LogField_Stringvalue{Stringvalue: "retry"}},
+			{Key: "attempt", Value: &LogField_Int64Value{Int64Value: 2}},
+		}}},
+		Links: []*SpanLink{{Type: SpanReferenceType_FollowsFrom, TraceID: 5, SpanID: 6}},
+	}
+	ext.SpanKindRPCServer.Set(sp)
+	ext.HTTPMethod.Set(sp, "POST")
+	ext.HTTPStatusCode.Set(sp, 500)
+	ext.Error.Set(sp, true)
+
+	return &Trace{Trace: []*Span{sp}}
+}
+
+func checkOTLPRequest(t *testing.T, req *coltracepb.ExportTraceServiceRequest) {
+	t.Helper()
+
+	if len(req.ResourceSpans) != 1 {
+		t.Fatalf("expected 1 resource spans, got %d", len(req.ResourceSpans))
+	}
+	rs := req.ResourceSpans[0]
+	if v := rs.Resource.Attributes[0]; v.Key != "service.name" || v.Value.GetStringValue() != "checkout" {
+		t.Errorf("unexpected resource attribute %v", v)
+	}
+	span := rs.ScopeSpans[0].Spans[0]
+	if !bytes.Equal(span.TraceId, []byte{0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 2}) {
+		t.Errorf("unexpected trace id %x", span.TraceId)
+	}
+	if !bytes.Equal(span.SpanId, []byte{0, 0, 0, 0, 0, 0, 0, 3}) || !bytes.Equal(span.ParentSpanId, []byte{0, 0, 0, 0, 0, 0, 0, 4}) {
+		t.Errorf("unexpected span id %x or parent id %x", span.SpanId, span.ParentSpanId)
+	}
+	if span.Name != "POST /orders" || span.Kind != tracepb.Span_SPAN_KIND_SERVER {
+		t.Errorf("unexpected name %q or kind %s", span.Name, span.Kind)
+	}
+	if span.StartTimeUnixNano != 1000 || span.EndTimeUnixNano != 2000 {
+		t.Errorf("unexpected timestamps %d %d", span.StartTimeUnixNano, span.EndTimeUnixNano)
+	}
+	if span.Status.Code != tracepb.Status_STATUS_CODE_ERROR {
+		t.Errorf("unexpected status %s", span.Status.Code)
+	}
+	attrs := make(map[string]interface{})
+	for _, kv := range span.Attributes {
+		switch v := kv.Value.Value.(type) {
+		case *commonpb.AnyValue_StringValue:
+			attrs[kv.Key] = v.StringValue
+		case *commonpb.AnyValue_BoolValue:
+			attrs[kv.Key] = v.BoolValue
+		default:
+			attrs[kv.Key] = kv.Value.GetIntValue()
+		}
+	}
+	if attrs["http.method"] != "POST" || attrs["http.status_code"] != int64(500) || attrs["error"] != true {
+		t.Errorf("unexpected attributes %v", attrs)
+	}
+	if len(span.Events) != 1 || span.Events[0].Name != "retry" || span.Events[0].Attributes[0].Value.GetIntValue() != 2 {
+		t.Errorf("unexpected events %v", span.Events)
+	}
+	if len(span.Links) != 1 || span.Links[0].Attributes[0].Value.GetStringValue() != "follows_from" {
+		t.Errorf("unexpected links %v", span.Links)
+	}
+}
+
+func TestOTLPHTTPExporter(t *testing.T) {
+	var calls int32
+	svr := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			resp.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+		if req.URL.Path != "/v1/traces" || req.Header.Get("Content-Type") != "application/x-protobuf" {
+			t.Errorf("unexpected request %s %s", req.URL.Path, req.Header.Get("Content-Type"))
+		}
+		if req.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("missing authorization header")
+		}
+		zr, err := gzip.NewReader(req.Body)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		bts, _ := io.ReadAll(zr)
+		otlpreq := &coltracepb.ExportTraceServiceRequest{}
+		if err = proto.Unmarshal(bts, otlpreq); err != nil {
+			t.Fatal(err.Error())
+		}
+		checkOTLPRequest(t, otlpreq)
+
+		bts, _ = proto.Marshal(&coltracepb.ExportTraceServiceResponse{})
+		resp.Write(bts)
+	}))
+	defer svr.Close()
+
+	exporter := NewOTLPHTTPExporter(svr.URL,
+		WithOTLPHTTPHeaders(map[string]string{"Authorization": "Bearer token"}),
+		WithOTLPHTTPRetry(2, time.Millisecond),
+	)
+	if err := exporter.Export(context.Background(), testOTLPTrace()); err != nil {
+		t.Fatal(err.Error())
+	}
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Errorf("expected 1 retry, got %d calls", n)
+	}
+
+	exporter.Shutdown(context.Background())
+	if err := exporter.Export(context.Background(), testOTLPTrace()); err == nil {
+		t.Error("expected error after shutdown")
+	}
+}
+
+func TestOTLPHTTPExporterRetryElapsed(t *testing.T) {
+	var calls int32
+	svr := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		resp.Header().Set("Retry-After", "3600")
+		resp.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer svr.Close()
+
+	exporter := NewOTLPHTTPExporter(svr.URL)
+	start := time.Now()
+	if err := exporter.Export(context.Background(), testOTLPTrace()); err == nil {
+		t.Error("expected unavailable error")
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("export waited %s past its max elapsed time", d)
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("expected no retry, got %d calls", n)
+	}
+}
+
+func TestOTLPHTTPExporterPartialSuccess(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		bts, _ := proto.Marshal(&coltracepb.ExportTraceServiceResponse{
+			PartialSuccess: &coltracepb.ExportTracePartialSuccess{RejectedSpans: 1, ErrorMessage: "span too large"},
+		})
+		resp.Write(bts)
+	}))
+	defer svr.Close()
+
+	exporter := NewOTLPHTTPExporter(svr.URL, WithOTLPHTTPCompression(false))
+	if err := exporter.Export(context.Background(), testOTLPTrace()); err == nil {
+		t.Error("expected partial success error")
+	}
+}
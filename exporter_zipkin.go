@@ -0,0 +1,235 @@
+package optcgo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/opentracing/opentracing-go/ext"
+)
+
+const (
+	DefZipkinEndpoint = "http://localhost:9411"
+	zipkinSpansPath   = "/api/v2/spans"
+)
+
+type ZipkinOption func(exporter *ZipkinExporter)
+
+// WithZipkinHeaders adds headers to every export request.
+func WithZipkinHeaders(headers map[string]string) ZipkinOption {
+	return func(exporter *ZipkinExporter) {
+		for k, v := range headers {
+			exporter.headers.Set(k, v)
+		}
+	}
+}
+
+func WithZipkinClient(client *http.Client) ZipkinOption {
+	return func(exporter *ZipkinExporter) {
+		exporter.client = client
+	}
+}
+
+var _ Exporter = (*ZipkinExporter)(nil)
+
+// ZipkinExporter posts traces as Zipkin v2 JSON to the /api/v2/spans path
+// of a Zipkin server.
+type ZipkinExporter struct {
+	url      string
+	headers  http.Header
+	client   *http.Client
+	shutdown int32
+}
+
+// NewZipkinExporter creates exporter for the Zipkin server at endpoint,
+// e.g. http://localhost:9411.
+func NewZipkinExporter(endpoint string, opts ...ZipkinOption) *ZipkinExporter {
+	if endpoint == "" {
+		endpoint = DefZipkinEndpoint
+	}
+	exporter := &ZipkinExporter{
+		url:     strings.TrimSuffix(endpoint, "/") + zipkinSpansPath,
+		headers: make(http.Header),
+		client:  &http.Client{Timeout: DefExporterTimeout},
+	}
+	for i := range opts {
+		opts[i](exporter)
+	}
+
+	return exporter
+}
+
+func (exp *ZipkinExporter) Export(ctx context.Context, trace *Trace) error {
+	if atomic.LoadInt32(&exp.shutdown) != 0 {
+		return errExporterShutdown
+	}
+	if len(trace.Trace) == 0 {
+		return nil
+	}
+
+	spans := make([]*zipkinSpan, len(trace.Trace))
+	for i, sp := range trace.Trace {
+		spans[i] = spanToZipkin(sp)
+	}
+	body, err := json.Marshal(spans)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, exp.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for k, v := range exp.headers {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := exp.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("export to zipkin failed with status: %s", resp.Status)
+	}
+
+	return nil
+}
+
+func (exp *ZipkinExporter) Shutdown(ctx context.Context) error {
+	atomic.StoreInt32(&exp.shutdown, 1)
+	exp.client.CloseIdleConnections()
+
+	return nil
+}
+
+type zipkinEndpoint struct {
+	ServiceName string `json:"serviceName,omitempty"`
+}
+
+type zipkinAnnotation struct {
+	Timestamp int64  `json:"timestamp"`
+	Value     string `json:"value"`
+}
+
+type zipkinSpan struct {
+	TraceID       string             `json:"traceId"`
+	ID            string             `json:"id"`
+	ParentID      string             `json:"parentId,omitempty"`
+	Name          string             `json:"name,omitempty"`
+	Kind          string             `json:"kind,omitempty"`
+	Timestamp     int64              `json:"timestamp,omitempty"`
+	Duration      int64              `json:"duration,omitempty"`
+	LocalEndpoint *zipkinEndpoint    `json:"localEndpoint,omitempty"`
+	Annotations   []zipkinAnnotation `json:"annotations,omitempty"`
+	Tags          map[string]string  `json:"tags,omitempty"`
+}
+
+func spanToZipkin(sp *Span) *zipkinSpan {
+	span := &zipkinSpan{
+		TraceID:       formatTraceID(sp.TraceIDHigh, sp.TraceID),
+		ID:            fmt.Sprintf("%016x", uint64(sp.SpanID)),
+		Name:          sp.Operation,
+		Kind:          zipkinSpanKind(sp.Meta[string(ext.SpanKind)]),
+		Timestamp:     sp.StartTime / 1e3,
+		LocalEndpoint: &zipkinEndpoint{ServiceName: sp.Service},
+		Tags:          make(map[string]string, len(sp.Meta)+len(sp.Metrics)+1),
+	}
+	if sp.ParentID != 0 {
+		span.ParentID = fmt.Sprintf("%016x", uint64(sp.ParentID))
+	}
+	// zipkin requires duration of at least one microsecond
+	if d := sp.EndTime - sp.StartTime; d > 0 {
+		if span.Duration = d / 1e3; span.Duration == 0 {
+			span.Duration = 1
+		}
+	}
+	for k, v := range sp.Meta {
+		if k != string(ext.SpanKind) {
+			span.Tags[k] = v
+		}
+	}
+	for k, v := range sp.Metrics {
+		if s, ok := numericString(v); ok {
+			span.Tags[k] = s
+		}
+	}
+	switch sp.Status {
+	case SpanStatus_Error, SpanStatus_Crisis:
+		if _, ok := span.Tags[string(ext.Error)]; !ok {
+			span.Tags[string(ext.Error)] = sp.Status.String()
+		}
+	default:
+		// zipkin marks any span having the error tag as failed
+		if span.Tags[string(ext.Error)] == "false" {
+			delete(span.Tags, string(ext.Error))
+		}
+	}
+	for _, l := range sp.Logs {
+		span.Annotations = append(span.Annotations, logToZipkin(l))
+	}
+
+	return span
+}
+
+// logToZipkin converts span log into annotation valued with its event field
+// if it is the only field, or with all fields in key=value form otherwise.
+func logToZipkin(l *SpanLog) zipkinAnnotation {
+	annotation := zipkinAnnotation{Timestamp: l.Timestamp / 1e3}
+	if len(l.Fields) == 1 && l.Fields[0].Key == "event" {
+		annotation.Value = l.Fields[0].GetStringvalue()
+
+		return annotation
+	}
+
+	pairs := make([]string, 0, len(l.Fields))
+	for _, f := range l.Fields {
+		var value string
+		switch v := f.Value.(type) {
+		case *LogField_Stringvalue:
+			value = v.Stringvalue
+		case *LogField_Boolvalue:
+			value = strconv.FormatBool(v.Boolvalue)
+		case *LogField_Int64Value:
+			value = strconv.FormatInt(v.Int64Value, 10)
+		case *LogField_Uint64Value:
+			value = strconv.FormatUint(v.Uint64Value, 10)
+		case *LogField_Doublevalue:
+			value = strconv.FormatFloat(v.Doublevalue, 'g', -1, 64)
+		case *LogField_Errorvalue:
+			value = v.Errorvalue
+		case *LogField_Objectvalue:
+			value = v.Objectvalue
+		default:
+			continue
+		}
+		pairs = append(pairs, f.Key+"="+value)
+	}
+	annotation.Value = strings.Join(pairs, " ")
+
+	return annotation
+}
+
+func zipkinSpanKind(kind string) string {
+	switch ext.SpanKindEnum(kind) {
+	case ext.SpanKindRPCServerEnum:
+		return "SERVER"
+	case ext.SpanKindRPCClientEnum:
+		return "CLIENT"
+	case ext.SpanKindProducerEnum:
+		return "PRODUCER"
+	case ext.SpanKindConsumerEnum:
+		return "CONSUMER"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,102 @@
+package optcgo
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/opentracing/opentracing-go/ext"
+)
+
+func TestZipkinExporter(t *testing.T) {
+	var spans []map[string]interface{}
+	svr := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/api/v2/spans" || req.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected request %s %s", req.URL.Path, req.Header.Get("Content-Type"))
+		}
+		if err := json.NewDecoder(req.Body).Decode(&spans); err != nil {
+			t.Error(err.Error())
+		}
+		resp.WriteHeader(http.StatusAccepted)
+	}))
+	defer svr.Close()
+
+	trace := testOTLPTrace()
+	trace.Trace[0].StartTime = 1_000_000
+	trace.Trace[0].EndTime = 3_500_000
+	trace.Trace[0].Logs[0].Timestamp = 2_000_000
+	exporter := NewZipkinExporter(svr.URL)
+	if err := exporter.Export(context.Background(), trace); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	span := spans[0]
+	if span["traceId"] != "00000000000000010000000000000002" || span["id"] != "0000000000000003" || span["parentId"] != "0000000000000004" {
+		t.Errorf("unexpected ids %v %v %v", span["traceId"], span["id"], span["parentId"])
+	}
+	if span["name"] != "POST /orders" || span["kind"] != "SERVER" {
+		t.Errorf("unexpected name %v or kind %v", span["name"], span["kind"])
+	}
+	if span["timestamp"] != float64(1000) || span["duration"] != float64(2500) {
+		t.Errorf("unexpected timestamp %v or duration %v", span["timestamp"], span["duration"])
+	}
+	if ep, _ := span["localEndpoint"].(map[string]interface{}); ep["serviceName"] != "checkout" {
+		t.Errorf("unexpected local endpoint %v", span["localEndpoint"])
+	}
+	tags, _ := span["tags"].(map[string]interface{})
+	if tags["http.method"] != "POST" || tags["http.status_code"] != "500" || tags["error"] != "true" {
+		t.Errorf("unexpected tags %v", tags)
+	}
+	annotations, _ := span["annotations"].([]interface{})
+	if len(annotations) != 1 {
+		t.Fatalf("expected 1 annotation, got %v", annotations)
+	}
+	if a := annotations[0].(map[string]interface{}); a["timestamp"] != float64(2000) || a["value"] != "event=retry attempt=2" {
+		t.Errorf("unexpected annotation %v", a)
+	}
+}
+
+func TestZipkinSpanTags(t *testing.T) {
+	sp := &Span{TraceID: 1, SpanID: 2}
+	ext.SpanKindProducer.Set(sp)
+	ext.PeerPort.Set(sp, 5672)
+	ext.Error.Set(sp, true)
+	ext.Error.Set(sp, false)
+	sp.SetTag("retry", true)
+
+	span := spanToZipkin(sp)
+	if span.Kind != "PRODUCER" {
+		t.Errorf("unexpected kind %q", span.Kind)
+	}
+	if _, ok := span.Tags[string(ext.SpanKind)]; ok {
+		t.Error("span kind duplicated in tags")
+	}
+	if _, ok := span.Tags[string(ext.Error)]; ok {
+		t.Error("error tag false kept")
+	}
+	if span.Tags[string(ext.PeerPort)] != "5672" || span.Tags["retry"] != "true" {
+		t.Errorf("unexpected tags %v", span.Tags)
+	}
+}
+
+func TestZipkinExporterError(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		resp.WriteHeader(http.StatusBadRequest)
+	}))
+	defer svr.Close()
+
+	exporter := NewZipkinExporter(svr.URL)
+	if err := exporter.Export(context.Background(), testOTLPTrace()); err == nil {
+		t.Error("expected error on bad request")
+	}
+
+	exporter.Shutdown(context.Background())
+	if err := exporter.Export(context.Background(), testOTLPTrace()); err != errExporterShutdown {
+		t.Errorf("expected shutdown error, got %v", err)
+	}
+}
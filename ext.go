@@ -1,5 +1,10 @@
 package optcgo
 
+import (
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+)
+
 // Prefix for universal-opentracing-transformer key in k-v paris
 const Prefix = "uni-ot-"
 
@@ -10,6 +15,7 @@ const (
 	SamplePriorityKey = "uni-ot-smaple-priority"
 	SampleRatioKey    = "uni-ot-sample-ratio"
 	ExternalTraceID   = "uni-ot-external-trace-id"
+	PropagationKey    = "uni-ot-propagation"
 )
 
 // Trace key
@@ -19,3 +25,29 @@ const (
 )
 
 type FormatExternalTraceID func(tid interface{}) int64
+
+// SamplingPriorityTag forces the sampling decision of the trace when set on
+// a span, positive numbers or true keep the trace and zero or false block
+// it. It is the same tag as ext.SamplingPriority.
+var SamplingPriorityTag = string(ext.SamplingPriority)
+
+// UserKeep is a StartSpanOption forcing the trace of the new span to be kept.
+func UserKeep() opentracing.StartSpanOption {
+	return opentracing.Tag{Key: SamplingPriorityTag, Value: SamplePriority_UserKeep}
+}
+
+// UserBlock is a StartSpanOption forcing the trace of the new span to be
+// dropped.
+func UserBlock() opentracing.StartSpanOption {
+	return opentracing.Tag{Key: SamplingPriorityTag, Value: SamplePriority_UserBlock}
+}
+
+// SetUserKeep forces the trace of span to be kept.
+func SetUserKeep(span opentracing.Span) {
+	span.SetTag(SamplingPriorityTag, SamplePriority_UserKeep)
+}
+
+// SetUserBlock forces the trace of span to be dropped.
+func SetUserBlock(span opentracing.Span) {
+	span.SetTag(SamplingPriorityTag, SamplePriority_UserBlock)
+}
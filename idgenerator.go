@@ -0,0 +1,137 @@
+package optcgo
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// IDGenerator generates trace and span ids for new spans, ids are positive
+// and never zero. The high 64 bits of trace id are only used when the Tracer
+// is started WithTraceID128.
+type IDGenerator interface {
+	NewTraceID() (high, low int64)
+	NewSpanID() int64
+}
+
+func WithIDGenerator(generator IDGenerator) StartTracerOption {
+	return func(tracer *Tracer) {
+		tracer.idGenerator = generator
+	}
+}
+
+// WithTraceID128 enables 128 bits trace ids for root spans, the high 64
+// bits are kept in TraceIDHigh of Span and SpanContext.
+func WithTraceID128(enable bool) StartTracerOption {
+	return func(tracer *Tracer) {
+		tracer.traceID128 = enable
+	}
+}
+
+var _ IDGenerator = (*RandomIDGenerator)(nil)
+
+// RandomIDGenerator is the default IDGenerator of Tracer. It keeps a pool of
+// math/rand sources seeded from crypto/rand, so concurrent goroutines do not
+// contend on the lock of the global source.
+type RandomIDGenerator struct {
+	pool sync.Pool
+}
+
+func NewRandomIDGenerator() *RandomIDGenerator {
+	return &RandomIDGenerator{
+		pool: sync.Pool{
+			New: func() interface{} {
+				return rand.New(rand.NewSource(cryptoSeed()))
+			},
+		},
+	}
+}
+
+func (rig *RandomIDGenerator) NewTraceID() (high, low int64) {
+	r := rig.pool.Get().(*rand.Rand)
+	high, low = nonZeroInt63(r), nonZeroInt63(r)
+	rig.pool.Put(r)
+
+	return
+}
+
+func (rig *RandomIDGenerator) NewSpanID() int64 {
+	r := rig.pool.Get().(*rand.Rand)
+	id := nonZeroInt63(r)
+	rig.pool.Put(r)
+
+	return id
+}
+
+var _ IDGenerator = CryptoIDGenerator{}
+
+// CryptoIDGenerator reads every id from crypto/rand, it is slower than
+// RandomIDGenerator but ids are unpredictable.
+type CryptoIDGenerator struct{}
+
+func (CryptoIDGenerator) NewTraceID() (high, low int64) {
+	return cryptoInt63(), cryptoInt63()
+}
+
+func (CryptoIDGenerator) NewSpanID() int64 {
+	return cryptoInt63()
+}
+
+var _ IDGenerator = (*SeededIDGenerator)(nil)
+
+// SeededIDGenerator generates the same sequence of ids for the same seed,
+// it is meant for tests.
+type SeededIDGenerator struct {
+	sync.Mutex
+	r *rand.Rand
+}
+
+func NewSeededIDGenerator(seed int64) *SeededIDGenerator {
+	return &SeededIDGenerator{r: rand.New(rand.NewSource(seed))}
+}
+
+func (sig *SeededIDGenerator) NewTraceID() (high, low int64) {
+	sig.Lock()
+	defer sig.Unlock()
+
+	return nonZeroInt63(sig.r), nonZeroInt63(sig.r)
+}
+
+func (sig *SeededIDGenerator) NewSpanID() int64 {
+	sig.Lock()
+	defer sig.Unlock()
+
+	return nonZeroInt63(sig.r)
+}
+
+func nonZeroInt63(r *rand.Rand) int64 {
+	for {
+		if id := r.Int63(); id != 0 {
+			return id
+		}
+	}
+}
+
+func cryptoInt63() int64 {
+	var buf [8]byte
+	for {
+		if _, err := crand.Read(buf[:]); err != nil {
+			// crypto/rand is not expected to fail, fall back to a time based seed
+			return nonZeroInt63(rand.New(rand.NewSource(time.Now().UnixNano())))
+		}
+		if id := int64(binary.BigEndian.Uint64(buf[:]) >> 1); id != 0 {
+			return id
+		}
+	}
+}
+
+func cryptoSeed() int64 {
+	var buf [8]byte
+	if _, err := crand.Read(buf[:]); err != nil {
+		return time.Now().UnixNano()
+	}
+
+	return int64(binary.BigEndian.Uint64(buf[:]))
+}
@@ -0,0 +1,48 @@
+package optcgo
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestSeededIDGenerator(t *testing.T) {
+	tracer1 := NewTracer("test_service", WithIDGenerator(NewSeededIDGenerator(42)), WithTraceID128(true))
+	tracer2 := NewTracer("test_service", WithIDGenerator(NewSeededIDGenerator(42)), WithTraceID128(true))
+	for i := 0; i < 10; i++ {
+		sp1, sp2 := tracer1.StartSpan("op").(*Span), tracer2.StartSpan("op").(*Span)
+		if sp1.TraceIDHigh != sp2.TraceIDHigh || sp1.TraceID != sp2.TraceID || sp1.SpanID != sp2.SpanID {
+			t.Fatalf("seeded generators diverged: %v != %v", sp1, sp2)
+		}
+	}
+}
+
+func TestIDGeneratorsConcurrent(t *testing.T) {
+	for _, generator := range []IDGenerator{NewRandomIDGenerator(), CryptoIDGenerator{}, NewSeededIDGenerator(1)} {
+		var (
+			wg   sync.WaitGroup
+			lock sync.Mutex
+			ids  = make(map[int64]bool)
+		)
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				for j := 0; j < 1000; j++ {
+					high, low := generator.NewTraceID()
+					id := generator.NewSpanID()
+					if high <= 0 || low <= 0 || id <= 0 {
+						t.Errorf("%T: non positive id %d %d %d", generator, high, low, id)
+					}
+					lock.Lock()
+					if ids[id] {
+						t.Errorf("%T: duplicated span id %d", generator, id)
+					}
+					ids[id] = true
+					lock.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+	}
+}
@@ -0,0 +1,128 @@
+package optcgo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+// Propagator injects SpanContext into and extracts it from text map
+// carriers, it backs the opentracing.HTTPHeaders and opentracing.TextMap
+// formats of Tracer.
+type Propagator interface {
+	Inject(spctx *SpanContext, writer opentracing.TextMapWriter) error
+	Extract(reader opentracing.TextMapReader) (*SpanContext, error)
+}
+
+// formatTraceID formats trace id as 16 hex characters, or as 32 hex
+// characters if the high 64 bits are set.
+func formatTraceID(high, low int64) string {
+	if high == 0 {
+		return fmt.Sprintf("%016x", uint64(low))
+	}
+
+	return fmt.Sprintf("%016x%016x", uint64(high), uint64(low))
+}
+
+// parseTraceID parses trace id of at most 32 hex characters into its high
+// and low 64 bits.
+func parseTraceID(id string) (high, low int64, err error) {
+	l := len(id)
+	if l == 0 || l > 32 {
+		return 0, 0, opentracing.ErrSpanContextCorrupted
+	}
+
+	var h, lo uint64
+	if l > 16 {
+		if h, err = strconv.ParseUint(id[:l-16], 16, 64); err != nil {
+			return 0, 0, opentracing.ErrSpanContextCorrupted
+		}
+		id = id[l-16:]
+	}
+	if lo, err = strconv.ParseUint(id, 16, 64); err != nil {
+		return 0, 0, opentracing.ErrSpanContextCorrupted
+	}
+	if h == 0 && lo == 0 {
+		return 0, 0, opentracing.ErrSpanContextCorrupted
+	}
+
+	return int64(h), int64(lo), nil
+}
+
+func isKeep(priority SamplePriority) bool {
+	switch priority {
+	case SamplePriority_AutoBlock, SamplePriority_SamplerBlock, SamplePriority_UserBlock:
+		return false
+	default:
+		return true
+	}
+}
+
+var _ Propagator = CompositePropagator{}
+
+// CompositePropagator injects SpanContext with all of its Propagators and
+// extracts it with the first Propagator that succeeds, in order.
+type CompositePropagator []Propagator
+
+func (cp CompositePropagator) Inject(spctx *SpanContext, writer opentracing.TextMapWriter) error {
+	for _, p := range cp {
+		if err := p.Inject(spctx, writer); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (cp CompositePropagator) Extract(reader opentracing.TextMapReader) (*SpanContext, error) {
+	err := opentracing.ErrSpanContextNotFound
+	for _, p := range cp {
+		spctx, e := p.Extract(reader)
+		if e == nil {
+			return spctx, nil
+		}
+		if err == opentracing.ErrSpanContextNotFound {
+			err = e
+		}
+	}
+
+	return nil, err
+}
+
+// Propagator names accepted by ParsePropagators, they follow the names of
+// the OpenTelemetry OTEL_PROPAGATORS variable.
+const (
+	PropagatorTraceContext = "tracecontext"
+	PropagatorB3           = "b3"
+	PropagatorB3Multi      = "b3multi"
+	PropagatorJaeger       = "jaeger"
+)
+
+// ParsePropagators builds a CompositePropagator from a comma separated list
+// of propagator names, e.g. "tracecontext,b3multi,jaeger". Unknown names are
+// ignored, nil is returned if no name is recognized.
+func ParsePropagators(names string) Propagator {
+	var cp CompositePropagator
+	for _, name := range strings.Split(names, ",") {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case PropagatorTraceContext:
+			cp = append(cp, W3CPropagator{})
+		case PropagatorB3:
+			cp = append(cp, B3Propagator{SingleHeader: true})
+		case PropagatorB3Multi:
+			cp = append(cp, B3Propagator{})
+		case PropagatorJaeger:
+			cp = append(cp, JaegerPropagator{})
+		}
+	}
+	switch len(cp) {
+	case 0:
+		return nil
+	case 1:
+		return cp[0]
+	default:
+		return cp
+	}
+}
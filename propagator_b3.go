@@ -0,0 +1,145 @@
+package optcgo
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+// Zipkin B3 headers, see https://github.com/openzipkin/b3-propagation
+const (
+	B3TraceIDHeader      = "x-b3-traceid"
+	B3SpanIDHeader       = "x-b3-spanid"
+	B3ParentSpanIDHeader = "x-b3-parentspanid"
+	B3SampledHeader      = "x-b3-sampled"
+	B3FlagsHeader        = "x-b3-flags"
+	B3SingleHeader       = "b3"
+	// baggage prefix used by OpenTracing Zipkin tracers
+	B3BaggageHeaderPrefix = "ot-baggage-"
+)
+
+var _ Propagator = B3Propagator{}
+
+// B3Propagator propagates SpanContext through Zipkin B3 headers. Extract
+// accepts both the single b3 header and the multiple X-B3-* headers, Inject
+// writes the single header when SingleHeader is set. The Sampled flag maps
+// to SamplePriority_UserKeep/UserBlock, so children always follow it, and
+// 128 bits trace ids are written when TraceIDHigh is set. SpanContext.Meta
+// is carried by ot-baggage-{key} headers.
+type B3Propagator struct {
+	SingleHeader bool
+}
+
+func (b3 B3Propagator) Inject(spctx *SpanContext, writer opentracing.TextMapWriter) error {
+	if (spctx.TraceIDHigh == 0 && spctx.TraceID == 0) || spctx.ParentID == 0 {
+		return opentracing.ErrInvalidSpanContext
+	}
+
+	sampled := "0"
+	if isKeep(spctx.SamplePriority) {
+		sampled = "1"
+	}
+	traceID := formatTraceID(spctx.TraceIDHigh, spctx.TraceID)
+	spanID := fmt.Sprintf("%016x", uint64(spctx.ParentID))
+	if b3.SingleHeader {
+		writer.Set(B3SingleHeader, traceID+"-"+spanID+"-"+sampled)
+	} else {
+		writer.Set(B3TraceIDHeader, traceID)
+		writer.Set(B3SpanIDHeader, spanID)
+		writer.Set(B3SampledHeader, sampled)
+	}
+	for k, v := range spctx.Meta {
+		writer.Set(B3BaggageHeaderPrefix+k, url.QueryEscape(v))
+	}
+
+	return nil
+}
+
+func (b3 B3Propagator) Extract(reader opentracing.TextMapReader) (*SpanContext, error) {
+	var (
+		single, traceID, spanID, sampled, flags string
+		baggage                                 = make(map[string]string)
+	)
+	err := reader.ForeachKey(func(key, val string) error {
+		lk := strings.ToLower(key)
+		if strings.HasPrefix(lk, B3BaggageHeaderPrefix) {
+			if v, err := url.QueryUnescape(val); err == nil {
+				val = v
+			}
+			baggage[lk[len(B3BaggageHeaderPrefix):]] = val
+
+			return nil
+		}
+
+		switch lk {
+		case B3SingleHeader:
+			single = val
+		case B3TraceIDHeader:
+			traceID = val
+		case B3SpanIDHeader:
+			spanID = val
+		case B3SampledHeader:
+			sampled = val
+		case B3FlagsHeader:
+			flags = val
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if single != "" {
+		parts := strings.Split(single, "-")
+		switch len(parts) {
+		case 1:
+			// sampling decision only, there is no context to continue
+			return nil, opentracing.ErrSpanContextNotFound
+		case 2:
+			traceID, spanID = parts[0], parts[1]
+		case 3, 4:
+			traceID, spanID, sampled = parts[0], parts[1], parts[2]
+		default:
+			return nil, opentracing.ErrSpanContextCorrupted
+		}
+		if sampled == "d" {
+			sampled, flags = "", "1"
+		}
+	}
+	if traceID == "" && spanID == "" {
+		return nil, opentracing.ErrSpanContextNotFound
+	}
+
+	if l := len(traceID); l != 16 && l != 32 {
+		return nil, opentracing.ErrSpanContextCorrupted
+	}
+	high, low, err := parseTraceID(traceID)
+	if err != nil {
+		return nil, err
+	}
+	if len(spanID) != 16 {
+		return nil, opentracing.ErrSpanContextCorrupted
+	}
+	sid, err := strconv.ParseUint(spanID, 16, 64)
+	if err != nil || sid == 0 {
+		return nil, opentracing.ErrSpanContextCorrupted
+	}
+	spctx := &SpanContext{TraceIDHigh: high, TraceID: low, ParentID: int64(sid)}
+	switch {
+	case flags == "1", sampled == "1", strings.EqualFold(sampled, "true"):
+		spctx.SamplePriority = SamplePriority_UserKeep
+	case sampled == "0" || strings.EqualFold(sampled, "false"):
+		spctx.SamplePriority = SamplePriority_UserBlock
+	case sampled != "":
+		return nil, opentracing.ErrSpanContextCorrupted
+	}
+	if len(baggage) != 0 {
+		spctx.Meta = baggage
+	}
+
+	return spctx, nil
+}
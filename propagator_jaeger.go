@@ -0,0 +1,129 @@
+package optcgo
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+// Jaeger propagation headers, see
+// https://www.jaegertracing.io/docs/latest/client-libraries/#propagation-format
+const (
+	JaegerTraceIDHeader       = "uber-trace-id"
+	JaegerBaggageHeaderPrefix = "uberctx-"
+	JaegerBaggageHeader       = "jaeger-baggage"
+)
+
+const (
+	jaegerFlagSampled = 0x01
+	jaegerFlagDebug   = 0x02
+)
+
+var _ Propagator = JaegerPropagator{}
+
+// JaegerPropagator propagates SpanContext through the Jaeger uber-trace-id
+// header, formatted as {trace-id}:{span-id}:{parent-span-id}:{flags}, and
+// carries SpanContext.Meta in uberctx-{key} baggage headers. The sampled flag
+// maps to SamplePriority_SamplerKeep/SamplerBlock and the debug flag to
+// SamplePriority_UserKeep.
+type JaegerPropagator struct{}
+
+func (JaegerPropagator) Inject(spctx *SpanContext, writer opentracing.TextMapWriter) error {
+	if (spctx.TraceIDHigh == 0 && spctx.TraceID == 0) || spctx.ParentID == 0 {
+		return opentracing.ErrInvalidSpanContext
+	}
+
+	var flags byte
+	if isKeep(spctx.SamplePriority) {
+		flags |= jaegerFlagSampled
+	}
+	if spctx.SamplePriority == SamplePriority_UserKeep {
+		flags |= jaegerFlagDebug
+	}
+	writer.Set(JaegerTraceIDHeader, fmt.Sprintf("%s:%016x:0:%x", formatTraceID(spctx.TraceIDHigh, spctx.TraceID), uint64(spctx.ParentID), flags))
+	for k, v := range spctx.Meta {
+		writer.Set(JaegerBaggageHeaderPrefix+k, url.QueryEscape(v))
+	}
+
+	return nil
+}
+
+func (JaegerPropagator) Extract(reader opentracing.TextMapReader) (*SpanContext, error) {
+	var (
+		uberTraceID string
+		baggage     = make(map[string]string)
+	)
+	err := reader.ForeachKey(func(key, val string) error {
+		lk := strings.ToLower(key)
+		switch {
+		case lk == JaegerTraceIDHeader:
+			uberTraceID = val
+		case strings.HasPrefix(lk, JaegerBaggageHeaderPrefix):
+			baggage[lk[len(JaegerBaggageHeaderPrefix):]] = unescapeJaegerValue(val)
+		case lk == JaegerBaggageHeader:
+			for _, item := range strings.Split(val, ",") {
+				if k, v, ok := strings.Cut(strings.TrimSpace(item), "="); ok {
+					baggage[k] = unescapeJaegerValue(v)
+				}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if uberTraceID == "" {
+		return nil, opentracing.ErrSpanContextNotFound
+	}
+
+	spctx, err := parseUberTraceID(unescapeJaegerValue(uberTraceID))
+	if err != nil {
+		return nil, err
+	}
+	if len(baggage) != 0 {
+		spctx.Meta = baggage
+	}
+
+	return spctx, nil
+}
+
+func parseUberTraceID(uberTraceID string) (*SpanContext, error) {
+	parts := strings.Split(uberTraceID, ":")
+	if len(parts) != 4 {
+		return nil, opentracing.ErrSpanContextCorrupted
+	}
+
+	high, low, err := parseTraceID(parts[0])
+	if err != nil {
+		return nil, err
+	}
+	sid, err1 := strconv.ParseUint(parts[1], 16, 64)
+	flags, err2 := strconv.ParseUint(parts[3], 16, 8)
+	if err1 != nil || err2 != nil || sid == 0 {
+		return nil, opentracing.ErrSpanContextCorrupted
+	}
+
+	spctx := &SpanContext{TraceIDHigh: high, TraceID: low, ParentID: int64(sid)}
+	switch {
+	case flags&jaegerFlagDebug != 0:
+		spctx.SamplePriority = SamplePriority_UserKeep
+	case flags&jaegerFlagSampled != 0:
+		spctx.SamplePriority = SamplePriority_SamplerKeep
+	default:
+		spctx.SamplePriority = SamplePriority_SamplerBlock
+	}
+
+	return spctx, nil
+}
+
+func unescapeJaegerValue(value string) string {
+	if v, err := url.QueryUnescape(value); err == nil {
+		return v
+	}
+
+	return value
+}
@@ -0,0 +1,272 @@
+package optcgo
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestW3CPropagation(t *testing.T) {
+	tracer := NewTracer("test_service")
+
+	spctx := &SpanContext{TraceID: 0x1234, ParentID: 0x5678, SamplePriority: SamplePriority_UserKeep, SampleRatio: 0.5}
+	header := http.Header{}
+	if err := tracer.Inject(spctx, opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(header)); err != nil {
+		t.Fatal(err.Error())
+	}
+	if v := header.Get(TraceParentHeader); v != "00-00000000000000000000000000001234-0000000000005678-01" {
+		t.Fatalf("unexpected traceparent %q", v)
+	}
+
+	got, err := tracer.Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(header))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	extracted := got.(*remoteSpanContext).SpanContext
+	if extracted.TraceID != spctx.TraceID || extracted.ParentID != spctx.ParentID ||
+		extracted.SamplePriority != spctx.SamplePriority || extracted.SampleRatio != spctx.SampleRatio {
+		t.Fatalf("extracted %v, want %v", extracted, spctx)
+	}
+}
+
+func TestW3CTraceState(t *testing.T) {
+	tracer := NewTracer("test_service")
+
+	remote, err := tracer.Extract(opentracing.TextMap, opentracing.TextMapCarrier{
+		TraceParentHeader: "00-00000000000000000000000000001234-0000000000005678-01",
+		TraceStateHeader:  "rojo=00f067aa0ba902b7, uniot=p:2;r:0.5,congo=t61rcWkgMzE",
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	span := tracer.StartSpan("server", opentracing.ChildOf(remote))
+	carrier := opentracing.TextMapCarrier{}
+	if err = tracer.Inject(span.Context(), opentracing.TextMap, carrier); err != nil {
+		t.Fatal(err.Error())
+	}
+	if v := carrier[TraceStateHeader]; v != "uniot=p:2;r:0.5,rojo=00f067aa0ba902b7,congo=t61rcWkgMzE" {
+		t.Errorf("unexpected tracestate %q", v)
+	}
+}
+
+func TestW3CExtractErrors(t *testing.T) {
+	tracer := NewTracer("test_service")
+
+	if _, err := tracer.Extract(opentracing.TextMap, opentracing.TextMapCarrier{}); err != opentracing.ErrSpanContextNotFound {
+		t.Errorf("expected ErrSpanContextNotFound, got %v", err)
+	}
+	for _, traceparent := range []string{
+		"00-00000000000000000000000000001234-0000000000005678",
+		"00-00000000000000000000000000000000-0000000000005678-01",
+		"00-00000000000000000000000000001234-0000000000000000-01",
+		"ff-00000000000000000000000000001234-0000000000005678-01",
+		"00-0000000000000000000000000000123x-0000000000005678-01",
+	} {
+		carrier := opentracing.TextMapCarrier{TraceParentHeader: traceparent}
+		if _, err := tracer.Extract(opentracing.TextMap, carrier); err != opentracing.ErrSpanContextCorrupted {
+			t.Errorf("traceparent %q: expected ErrSpanContextCorrupted, got %v", traceparent, err)
+		}
+	}
+
+	carrier := opentracing.TextMapCarrier{TraceParentHeader: "00-00000000000000000000000000001234-0000000000005678-00"}
+	spctx, err := tracer.Extract(opentracing.TextMap, carrier)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if p := spctx.(*remoteSpanContext).SamplePriority; isKeep(p) {
+		t.Errorf("unsampled traceparent extracted as %s", p)
+	}
+}
+
+func TestBinaryPropagation(t *testing.T) {
+	tracer := NewTracer("test_service")
+
+	spctx := &SpanContext{
+		TraceID:        0x1234,
+		ParentID:       0x5678,
+		SamplePriority: SamplePriority_SamplerKeep,
+		SampleRatio:    0.25,
+		Meta:           map[string]string{"user": "alice"},
+	}
+	buf := &bytes.Buffer{}
+	if err := tracer.Inject(spctx, opentracing.Binary, buf); err != nil {
+		t.Fatal(err.Error())
+	}
+	if buf.Bytes()[0] != BinaryVersion {
+		t.Fatalf("unexpected version byte %d", buf.Bytes()[0])
+	}
+
+	got, err := tracer.Extract(opentracing.Binary, buf)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	want := proto.Clone(spctx).(*SpanContext)
+	if !proto.Equal(got.(*remoteSpanContext).SpanContext, want) {
+		t.Fatalf("extracted %v, want %v", got, want)
+	}
+
+	if _, err = tracer.Extract(opentracing.Binary, &bytes.Buffer{}); err != opentracing.ErrSpanContextNotFound {
+		t.Errorf("expected ErrSpanContextNotFound, got %v", err)
+	}
+	if _, err = tracer.Extract(opentracing.Binary, bytes.NewReader([]byte{99, 0, 0, 0, 0})); err != opentracing.ErrSpanContextCorrupted {
+		t.Errorf("expected ErrSpanContextCorrupted, got %v", err)
+	}
+}
+
+func TestB3Propagation(t *testing.T) {
+	for _, priority := range []SamplePriority{SamplePriority_UserBlock, SamplePriority_UserKeep} {
+		spctx := &SpanContext{TraceID: 0x1234, ParentID: 0x5678, SamplePriority: priority}
+		for _, single := range []bool{false, true} {
+			tracer := NewTracer("test_service", WithPropagator(B3Propagator{SingleHeader: single}))
+			header := http.Header{}
+			if err := tracer.Inject(spctx, opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(header)); err != nil {
+				t.Fatal(err.Error())
+			}
+			got, err := tracer.Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(header))
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			extracted := got.(*remoteSpanContext).SpanContext
+			if extracted.TraceID != spctx.TraceID || extracted.ParentID != spctx.ParentID || extracted.SamplePriority != spctx.SamplePriority {
+				t.Errorf("single header %v: extracted %v, want %v", single, extracted, spctx)
+			}
+		}
+	}
+
+	tracer := NewTracer("test_service", WithPropagator(B3Propagator{}))
+	carrier := opentracing.TextMapCarrier{
+		"X-B3-TraceId": "463ac35c9f6413ad48485a3953bb6124",
+		"X-B3-SpanId":  "a2fb4a1d1a96d312",
+		"X-B3-Flags":   "1",
+	}
+	got, err := tracer.Extract(opentracing.TextMap, carrier)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if extracted := got.(*remoteSpanContext).SpanContext; extracted.TraceID != 0x48485a3953bb6124 || extracted.SamplePriority != SamplePriority_UserKeep {
+		t.Errorf("unexpected 128 bits extraction %v", extracted)
+	}
+	if _, err = tracer.Extract(opentracing.TextMap, opentracing.TextMapCarrier{"b3": "0"}); err != opentracing.ErrSpanContextNotFound {
+		t.Errorf("expected ErrSpanContextNotFound, got %v", err)
+	}
+}
+
+func TestJaegerPropagation(t *testing.T) {
+	tracer := NewTracer("test_service", WithPropagator(JaegerPropagator{}))
+
+	spctx := &SpanContext{
+		TraceID:        0x1234,
+		ParentID:       0x5678,
+		SamplePriority: SamplePriority_SamplerKeep,
+		Meta:           map[string]string{"user": "alice bob"},
+	}
+	header := http.Header{}
+	if err := tracer.Inject(spctx, opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(header)); err != nil {
+		t.Fatal(err.Error())
+	}
+	if v := header.Get(JaegerTraceIDHeader); v != "0000000000001234:0000000000005678:0:1" {
+		t.Fatalf("unexpected uber-trace-id %q", v)
+	}
+
+	got, err := tracer.Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(header))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	want := proto.Clone(spctx).(*SpanContext)
+	if !proto.Equal(got.(*remoteSpanContext).SpanContext, want) {
+		t.Fatalf("extracted %v, want %v", got, want)
+	}
+
+	carrier := opentracing.TextMapCarrier{JaegerTraceIDHeader: "1234:5678:0:x"}
+	if _, err = tracer.Extract(opentracing.TextMap, carrier); err != opentracing.ErrSpanContextCorrupted {
+		t.Errorf("expected ErrSpanContextCorrupted, got %v", err)
+	}
+}
+
+func TestCompositePropagation(t *testing.T) {
+	t.Setenv(PropagationKey, "b3multi,tracecontext")
+	tracer := NewTracer("test_service")
+
+	spctx := &SpanContext{TraceID: 0x1234, ParentID: 0x5678}
+	header := http.Header{}
+	if err := tracer.Inject(spctx, opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(header)); err != nil {
+		t.Fatal(err.Error())
+	}
+	if header.Get(B3TraceIDHeader) == "" || header.Get(TraceParentHeader) == "" {
+		t.Fatalf("expected both b3 and traceparent headers, got %v", header)
+	}
+
+	// b3 is corrupted, extraction falls back to traceparent
+	header.Set(B3SpanIDHeader, "xyz")
+	got, err := tracer.Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(header))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if extracted := got.(*remoteSpanContext).SpanContext; extracted.TraceID != spctx.TraceID || extracted.ParentID != spctx.ParentID {
+		t.Fatalf("extracted %v, want %v", extracted, spctx)
+	}
+
+	header.Del(TraceParentHeader)
+	if _, err = tracer.Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(header)); err != opentracing.ErrSpanContextCorrupted {
+		t.Errorf("expected ErrSpanContextCorrupted, got %v", err)
+	}
+}
+
+func TestTraceID128Propagation(t *testing.T) {
+	for _, propagator := range []Propagator{W3CPropagator{}, B3Propagator{}, B3Propagator{SingleHeader: true}, JaegerPropagator{}} {
+		tracer := NewTracer("test_service", WithTraceID128(true), WithPropagator(propagator))
+		sp := tracer.StartSpan("root").(*Span)
+		if sp.TraceIDHigh == 0 {
+			t.Fatal("expected 128 bits trace id")
+		}
+
+		carrier := opentracing.TextMapCarrier{}
+		if err := tracer.Inject(sp.Context(), opentracing.TextMap, carrier); err != nil {
+			t.Fatal(err.Error())
+		}
+		got, err := tracer.Extract(opentracing.TextMap, carrier)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		child := tracer.StartSpan("child", opentracing.ChildOf(got)).(*Span)
+		if child.TraceIDHigh != sp.TraceIDHigh || child.TraceID != sp.TraceID {
+			t.Errorf("%T: trace id %x%x not propagated, got %x%x", propagator, sp.TraceIDHigh, sp.TraceID, child.TraceIDHigh, child.TraceID)
+		}
+	}
+
+	tracer := NewTracer("test_service", WithPropagator(B3Propagator{}))
+	if sp := tracer.StartSpan("root").(*Span); sp.TraceIDHigh != 0 {
+		t.Errorf("unexpected 128 bits trace id %x%x with 64 bits tracer", sp.TraceIDHigh, sp.TraceID)
+	}
+}
+
+func TestTraceIDHighOnly(t *testing.T) {
+	tracer := NewTracer("test_service")
+	remote, err := tracer.Extract(opentracing.TextMap, opentracing.TextMapCarrier{
+		TraceParentHeader: "00-00000000000000010000000000000000-0000000000005678-01",
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	span := tracer.StartSpan("server", opentracing.ChildOf(remote)).(*Span)
+	if span.TraceIDHigh != 1 || span.TraceID != 0 || span.ParentID != 0x5678 {
+		t.Fatalf("expected trace to be continued, got %v", span)
+	}
+
+	for _, propagator := range []Propagator{W3CPropagator{}, B3Propagator{}, JaegerPropagator{}} {
+		carrier := opentracing.TextMapCarrier{}
+		if err = propagator.Inject(span.Context().(*SpanContext), carrier); err != nil {
+			t.Fatalf("%T: %s", propagator, err.Error())
+		}
+		got, err := propagator.Extract(carrier)
+		if err != nil {
+			t.Fatalf("%T: %s", propagator, err.Error())
+		}
+		if got.TraceIDHigh != 1 || got.TraceID != 0 || got.ParentID != span.SpanID {
+			t.Errorf("%T: extracted %v", propagator, got)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package optcgo
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+// W3C Trace Context headers, see https://www.w3.org/TR/trace-context/ and
+// https://www.w3.org/TR/baggage/
+const (
+	TraceParentHeader = "traceparent"
+	TraceStateHeader  = "tracestate"
+	BaggageHeader     = "baggage"
+	// vendor key of universal-opentracing-transformer in tracestate
+	TraceStateVendor = "uniot"
+)
+
+const (
+	w3cVersion     = "00"
+	w3cFlagSampled = 0x01
+	// max list members of tracestate
+	w3cMaxTraceStateMembers = 32
+)
+
+var _ Propagator = W3CPropagator{}
+
+// W3CPropagator propagates SpanContext through the W3C traceparent and
+// tracestate headers. TraceIDHigh and TraceID are the high and low halves of
+// the 128 bits W3C trace-id, the SamplePriority is kept in the sampled flag and
+// the exact priority and ratio are carried by the uniot tracestate entry,
+// which is written before the members of other vendors kept in
+// SpanContext.TraceState. SpanContext.Meta is carried by the baggage header.
+type W3CPropagator struct{}
+
+func (W3CPropagator) Inject(spctx *SpanContext, writer opentracing.TextMapWriter) error {
+	if (spctx.TraceIDHigh == 0 && spctx.TraceID == 0) || spctx.ParentID == 0 {
+		return opentracing.ErrInvalidSpanContext
+	}
+
+	var flags byte
+	if isKeep(spctx.SamplePriority) {
+		flags |= w3cFlagSampled
+	}
+	writer.Set(TraceParentHeader, fmt.Sprintf("%s-%016x%016x-%016x-%02x", w3cVersion, uint64(spctx.TraceIDHigh), uint64(spctx.TraceID), uint64(spctx.ParentID), flags))
+	tracestate := fmt.Sprintf("%s=p:%d;r:%s", TraceStateVendor, spctx.SamplePriority, strconv.FormatFloat(spctx.SampleRatio, 'g', -1, 64))
+	if spctx.TraceState != "" {
+		tracestate += "," + spctx.TraceState
+	}
+	writer.Set(TraceStateHeader, tracestate)
+	if len(spctx.Meta) != 0 {
+		members := make([]string, 0, len(spctx.Meta))
+		for k, v := range spctx.Meta {
+			members = append(members, k+"="+url.PathEscape(v))
+		}
+		writer.Set(BaggageHeader, strings.Join(members, ","))
+	}
+
+	return nil
+}
+
+func (W3CPropagator) Extract(reader opentracing.TextMapReader) (*SpanContext, error) {
+	var traceparent, tracestate, baggage string
+	err := reader.ForeachKey(func(key, val string) error {
+		switch strings.ToLower(key) {
+		case TraceParentHeader:
+			traceparent = val
+		case BaggageHeader:
+			if baggage == "" {
+				baggage = val
+			} else {
+				baggage += "," + val
+			}
+		case TraceStateHeader:
+			if tracestate == "" {
+				tracestate = val
+			} else {
+				tracestate += "," + val
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if traceparent == "" {
+		return nil, opentracing.ErrSpanContextNotFound
+	}
+
+	spctx, err := parseTraceParent(traceparent)
+	if err != nil {
+		return nil, err
+	}
+	parseTraceState(tracestate, spctx)
+	parseBaggage(baggage, spctx)
+
+	return spctx, nil
+}
+
+func parseTraceParent(traceparent string) (*SpanContext, error) {
+	parts := strings.Split(strings.TrimSpace(traceparent), "-")
+	if len(parts) < 4 {
+		return nil, opentracing.ErrSpanContextCorrupted
+	}
+	// future versions may append fields, version 00 must have exactly four
+	if len(parts[0]) != 2 || parts[0] == "ff" || (parts[0] == w3cVersion && len(parts) != 4) {
+		return nil, opentracing.ErrSpanContextCorrupted
+	}
+	if _, err := hex.DecodeString(parts[0]); err != nil {
+		return nil, opentracing.ErrSpanContextCorrupted
+	}
+	if len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return nil, opentracing.ErrSpanContextCorrupted
+	}
+
+	high, err1 := strconv.ParseUint(parts[1][:16], 16, 64)
+	low, err2 := strconv.ParseUint(parts[1][16:], 16, 64)
+	parent, err3 := strconv.ParseUint(parts[2], 16, 64)
+	flags, err4 := strconv.ParseUint(parts[3], 16, 8)
+	if err1 != nil || err2 != nil || err3 != nil || err4 != nil {
+		return nil, opentracing.ErrSpanContextCorrupted
+	}
+	if (high == 0 && low == 0) || parent == 0 {
+		return nil, opentracing.ErrSpanContextCorrupted
+	}
+
+	spctx := &SpanContext{
+		TraceIDHigh: int64(high),
+		TraceID:     int64(low),
+		ParentID:    int64(parent),
+	}
+	if flags&w3cFlagSampled == 0 {
+		spctx.SamplePriority = SamplePriority_AutoBlock
+	}
+
+	return spctx, nil
+}
+
+// parseTraceState reads the uniot entry from tracestate and keeps the members
+// of other vendors in SpanContext.TraceState, leaving room for the uniot
+// entry within the limit of list members. Malformed entries are ignored as
+// required by the specification.
+func parseTraceState(tracestate string, spctx *SpanContext) {
+	var (
+		others []string
+		seen   bool
+	)
+	for _, member := range strings.Split(tracestate, ",") {
+		member = strings.TrimSpace(member)
+		key, value, ok := strings.Cut(member, "=")
+		if !ok || key == "" {
+			continue
+		}
+		if key != TraceStateVendor {
+			if len(others) < w3cMaxTraceStateMembers-1 {
+				others = append(others, member)
+			}
+			continue
+		}
+		if seen {
+			continue
+		}
+		seen = true
+		for _, field := range strings.Split(value, ";") {
+			k, v, ok := strings.Cut(field, ":")
+			if !ok {
+				continue
+			}
+			switch k {
+			case "p":
+				if p, err := strconv.ParseInt(v, 10, 32); err == nil {
+					// the sampled flag of traceparent takes precedence over the vendor entry
+					if isKeep(SamplePriority(p)) == isKeep(spctx.SamplePriority) {
+						spctx.SamplePriority = SamplePriority(p)
+					}
+				}
+			case "r":
+				if r, err := strconv.ParseFloat(v, 64); err == nil {
+					spctx.SampleRatio = r
+				}
+			}
+		}
+	}
+	spctx.TraceState = strings.Join(others, ",")
+}
+
+// parseBaggage reads the members of baggage header into SpanContext.Meta,
+// member properties are dropped.
+func parseBaggage(baggage string, spctx *SpanContext) {
+	for _, member := range strings.Split(baggage, ",") {
+		member, _, _ = strings.Cut(member, ";")
+		key, value, ok := strings.Cut(member, "=")
+		if key = strings.TrimSpace(key); !ok || key == "" {
+			continue
+		}
+		if v, err := url.PathUnescape(strings.TrimSpace(value)); err == nil {
+			value = v
+		}
+		if spctx.Meta == nil {
+			spctx.Meta = make(map[string]string)
+		}
+		spctx.Meta[key] = value
+	}
+}
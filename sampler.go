@@ -1,6 +1,9 @@
 package optcgo
 
-import "math"
+import (
+	"math"
+	"time"
+)
 
 type Sampler interface {
 	Sample(id uint64, ratio float64) bool
@@ -22,3 +25,112 @@ func (cs CommonSampler) Sample(id uint64, ratio float64) bool {
 func (cs CommonSampler) Ratio() float64 {
 	return float64(cs)
 }
+
+// SpanSampler is a Sampler deciding with the attributes of the root span,
+// e.g. service, operation and tags, besides the trace id. It returns the
+// decision and the ratio the decision was made with.
+type SpanSampler interface {
+	Sampler
+	SampleSpan(span *Span) (keep bool, ratio float64)
+}
+
+// setSampling records the sampling decision on span.
+func setSampling(span *Span, priority SamplePriority, ratio float64) {
+	span.SetTag(SamplePriorityKey, &Numeric{Numeric: &Numeric_Int32Value{Int32Value: int32(priority)}})
+	span.SetTag(SampleRatioKey, &Numeric{Numeric: &Numeric_Doublevalue{Doublevalue: ratio}})
+}
+
+func (sp *Span) samplePriority() SamplePriority {
+	if p, ok := sp.Metrics[SamplePriorityKey]; ok {
+		return SamplePriority(p.GetInt32Value())
+	}
+
+	return SamplePriority_AutoKeep
+}
+
+// closeSampler stops the background work of sampler if it has any.
+func closeSampler(sampler Sampler) {
+	if closer, ok := sampler.(interface{ Close() }); ok {
+		closer.Close()
+	}
+}
+
+func isUserPriority(priority SamplePriority) bool {
+	return priority == SamplePriority_UserKeep || priority == SamplePriority_UserBlock
+}
+
+// user decisions are remembered per trace for DefForcedSamplingTTL
+const DefForcedSamplingTTL = 10 * time.Minute
+
+type forcedDecision struct {
+	priority SamplePriority
+	at       time.Time
+}
+
+// forceSampling records the user decision of span for its whole trace.
+func (tcr *Tracer) forceSampling(span *Span, priority SamplePriority) {
+	tcr.forced.Store(traceKey{high: span.TraceIDHigh, low: span.TraceID}, forcedDecision{priority: priority, at: time.Now()})
+}
+
+// applyForcedSampling overrides the sampling decision of span with the user
+// decision made for its trace, if any.
+func (tcr *Tracer) applyForcedSampling(span *Span) {
+	if fd, ok := tcr.forced.Load(traceKey{high: span.TraceIDHigh, low: span.TraceID}); ok {
+		if p := fd.(forcedDecision).priority; p != span.samplePriority() {
+			setSampling(span, p, span.Metrics[SampleRatioKey].GetDoublevalue())
+		}
+	}
+}
+
+func (tcr *Tracer) expireForcedSampling(now time.Time) {
+	tcr.forced.Range(func(key, value interface{}) bool {
+		if now.Sub(value.(forcedDecision).at) > DefForcedSamplingTTL {
+			tcr.forced.Delete(key)
+		}
+
+		return true
+	})
+}
+
+// userPriority converts the value of SamplingPriorityTag into
+// SamplePriority_UserKeep or SamplePriority_UserBlock, positive numbers and
+// true keep the trace while zero and false block it.
+func userPriority(value interface{}) (SamplePriority, bool) {
+	var keep bool
+	switch v := value.(type) {
+	case SamplePriority:
+		if isUserPriority(v) {
+			return v, true
+		}
+		keep = isKeep(v)
+	case bool:
+		keep = v
+	case int:
+		keep = v > 0
+	case int8:
+		keep = v > 0
+	case int16:
+		keep = v > 0
+	case int32:
+		keep = v > 0
+	case int64:
+		keep = v > 0
+	case uint:
+		keep = v > 0
+	case uint8:
+		keep = v > 0
+	case uint16:
+		keep = v > 0
+	case uint32:
+		keep = v > 0
+	case uint64:
+		keep = v > 0
+	default:
+		return SamplePriority_AutoKeep, false
+	}
+	if keep {
+		return SamplePriority_UserKeep, true
+	}
+
+	return SamplePriority_UserBlock, true
+}
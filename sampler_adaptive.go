@@ -0,0 +1,142 @@
+package optcgo
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	DefAdaptiveWindow        = 10 * time.Second
+	DefAdaptiveMaxOperations = 1000
+	// weight of the last window in the smoothed throughput
+	adaptiveSmoothing = 0.5
+)
+
+type adaptiveOperation struct {
+	count float64
+	rate  float64
+	ratio float64
+}
+
+var _ SpanSampler = (*AdaptiveSampler)(nil)
+
+// AdaptiveSampler adjusts the ratio of every operation so the total of
+// kept root spans converges on target per second. Throughput of operations
+// is measured over sliding windows and the budget is shared fairly, the
+// operations sending less than their share are fully kept and their unused
+// budget goes to the others. No operation is sampled below minRatio.
+type AdaptiveSampler struct {
+	sync.Mutex
+	target   float64
+	minRatio float64
+	window   time.Duration
+	start    time.Time
+	ops      map[string]*adaptiveOperation
+	ratio    float64
+	now      func() time.Time
+}
+
+func NewAdaptiveSampler(target, minRatio float64, window time.Duration) *AdaptiveSampler {
+	if window <= 0 {
+		window = DefAdaptiveWindow
+	}
+
+	return &AdaptiveSampler{
+		target:   target,
+		minRatio: minRatio,
+		window:   window,
+		ops:      make(map[string]*adaptiveOperation),
+		ratio:    1,
+		now:      time.Now,
+	}
+}
+
+func (as *AdaptiveSampler) Sample(id uint64, ratio float64) bool {
+	return CommonSampler(ratio).Sample(id, ratio)
+}
+
+// Ratio returns the effective ratio over all operations.
+func (as *AdaptiveSampler) Ratio() float64 {
+	as.Lock()
+	defer as.Unlock()
+
+	return as.ratio
+}
+
+func (as *AdaptiveSampler) SampleSpan(span *Span) (keep bool, ratio float64) {
+	as.Lock()
+	as.roll(as.now())
+	name := span.Operation
+	if _, ok := as.ops[name]; !ok && len(as.ops) >= DefAdaptiveMaxOperations {
+		// operations beyond the limit share one entry
+		name = ""
+	}
+	op, ok := as.ops[name]
+	if !ok {
+		op = &adaptiveOperation{ratio: 1}
+		as.ops[name] = op
+	}
+	op.count++
+	ratio = op.ratio
+	as.Unlock()
+
+	return as.Sample(uint64(span.TraceID), ratio), ratio
+}
+
+// roll closes the current window when it is over and recomputes ratios,
+// the caller must hold the lock.
+func (as *AdaptiveSampler) roll(now time.Time) {
+	if as.start.IsZero() {
+		as.start = now
+	}
+	elapsed := now.Sub(as.start)
+	if elapsed < as.window {
+		return
+	}
+
+	for name, op := range as.ops {
+		rate := op.count / elapsed.Seconds()
+		op.rate = adaptiveSmoothing*rate + (1-adaptiveSmoothing)*op.rate
+		op.count = 0
+		if op.rate < 1e-6 && name != "" {
+			delete(as.ops, name)
+		}
+	}
+	as.start = now
+	as.allocate()
+}
+
+// allocate shares target among operations from the least to the most busy.
+func (as *AdaptiveSampler) allocate() {
+	ops := make([]*adaptiveOperation, 0, len(as.ops))
+	for _, op := range as.ops {
+		ops = append(ops, op)
+	}
+	sort.Slice(ops, func(i, j int) bool { return ops[i].rate < ops[j].rate })
+
+	var (
+		budget     = as.target
+		seen, kept float64
+	)
+	for i, op := range ops {
+		share := budget / float64(len(ops)-i)
+		if op.rate <= share {
+			op.ratio = 1
+		} else {
+			op.ratio = share / op.rate
+		}
+		if op.ratio < as.minRatio {
+			op.ratio = as.minRatio
+		}
+		budget -= op.rate * op.ratio
+		if budget < 0 {
+			budget = 0
+		}
+		seen += op.rate
+		kept += op.rate * op.ratio
+	}
+	if seen > 0 {
+		as.ratio = kept / seen
+	}
+}
@@ -0,0 +1,118 @@
+package optcgo
+
+// ParentSampler is a Sampler also deciding for spans started with a
+// parent, without ParentSampler children follow the decision of their
+// parent.
+type ParentSampler interface {
+	Sampler
+	// SampleChild decides for span started with parent, remote reports
+	// whether parent was extracted from a remote process.
+	SampleChild(span *Span, parent *SpanContext, remote bool) (keep bool, ratio float64)
+}
+
+type ParentBasedOption func(sampler *ParentBasedSampler)
+
+// WithRemoteParentKeep sets the Sampler for children of kept remote parents.
+func WithRemoteParentKeep(sampler Sampler) ParentBasedOption {
+	return func(pbs *ParentBasedSampler) {
+		pbs.remoteKeep = sampler
+	}
+}
+
+// WithRemoteParentBlock sets the Sampler for children of blocked remote
+// parents.
+func WithRemoteParentBlock(sampler Sampler) ParentBasedOption {
+	return func(pbs *ParentBasedSampler) {
+		pbs.remoteBlock = sampler
+	}
+}
+
+// WithLocalParentKeep sets the Sampler for children of kept local parents.
+func WithLocalParentKeep(sampler Sampler) ParentBasedOption {
+	return func(pbs *ParentBasedSampler) {
+		pbs.localKeep = sampler
+	}
+}
+
+// WithLocalParentBlock sets the Sampler for children of blocked local
+// parents.
+func WithLocalParentBlock(sampler Sampler) ParentBasedOption {
+	return func(pbs *ParentBasedSampler) {
+		pbs.localBlock = sampler
+	}
+}
+
+var (
+	_ SpanSampler   = (*ParentBasedSampler)(nil)
+	_ ParentSampler = (*ParentBasedSampler)(nil)
+)
+
+// ParentBasedSampler delegates the decisions of root spans to the root
+// Sampler and the decisions of children to the Sampler configured for
+// their parent, depending on whether the parent was extracted from a remote
+// process and whether it was kept. Children whose parent has no configured
+// Sampler follow the decision of the parent.
+type ParentBasedSampler struct {
+	root        Sampler
+	remoteKeep  Sampler
+	remoteBlock Sampler
+	localKeep   Sampler
+	localBlock  Sampler
+}
+
+func NewParentBasedSampler(root Sampler, opts ...ParentBasedOption) *ParentBasedSampler {
+	pbs := &ParentBasedSampler{root: root}
+	for i := range opts {
+		opts[i](pbs)
+	}
+
+	return pbs
+}
+
+// Close closes the delegated samplers having background work, e.g.
+// RemoteSampler.
+func (pbs *ParentBasedSampler) Close() {
+	for _, sampler := range []Sampler{pbs.root, pbs.remoteKeep, pbs.remoteBlock, pbs.localKeep, pbs.localBlock} {
+		closeSampler(sampler)
+	}
+}
+
+func (pbs *ParentBasedSampler) Sample(id uint64, ratio float64) bool {
+	return pbs.root.Sample(id, ratio)
+}
+
+func (pbs *ParentBasedSampler) Ratio() float64 {
+	return pbs.root.Ratio()
+}
+
+func (pbs *ParentBasedSampler) SampleSpan(span *Span) (keep bool, ratio float64) {
+	return sampleWith(pbs.root, span)
+}
+
+func (pbs *ParentBasedSampler) SampleChild(span *Span, parent *SpanContext, remote bool) (keep bool, ratio float64) {
+	var sampler Sampler
+	switch parentKeep := isKeep(parent.SamplePriority); {
+	case remote && parentKeep:
+		sampler = pbs.remoteKeep
+	case remote:
+		sampler = pbs.remoteBlock
+	case parentKeep:
+		sampler = pbs.localKeep
+	default:
+		sampler = pbs.localBlock
+	}
+	if sampler == nil {
+		return isKeep(parent.SamplePriority), parent.SampleRatio
+	}
+
+	return sampleWith(sampler, span)
+}
+
+func sampleWith(sampler Sampler, span *Span) (keep bool, ratio float64) {
+	if ss, ok := sampler.(SpanSampler); ok {
+		return ss.SampleSpan(span)
+	}
+	ratio = sampler.Ratio()
+
+	return sampler.Sample(uint64(span.TraceID), ratio), ratio
+}
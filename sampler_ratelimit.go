@@ -0,0 +1,106 @@
+package optcgo
+
+import (
+	"sync"
+	"time"
+)
+
+var _ SpanSampler = (*RateLimitSampler)(nil)
+
+// RateLimitSampler keeps at most maxPerSecond root spans per second with a
+// token bucket holding up to one second of tokens. The ratio of kept to seen
+// root spans over the current and previous second is reported as the
+// effective ratio.
+type RateLimitSampler struct {
+	sync.Mutex
+	maxPerSecond float64
+	balance      float64
+	last         time.Time
+	window       time.Time
+	seen, kept   float64
+	// counters of previous window
+	pseen, pkept float64
+	now          func() time.Time
+}
+
+func NewRateLimitSampler(maxPerSecond float64) *RateLimitSampler {
+	if maxPerSecond < 0 {
+		maxPerSecond = 0
+	}
+
+	return &RateLimitSampler{
+		maxPerSecond: maxPerSecond,
+		balance:      maxPerSecond,
+		now:          time.Now,
+	}
+}
+
+func (rls *RateLimitSampler) Sample(id uint64, ratio float64) bool {
+	keep, _ := rls.sample()
+
+	return keep
+}
+
+// Ratio returns the effective ratio.
+func (rls *RateLimitSampler) Ratio() float64 {
+	rls.Lock()
+	defer rls.Unlock()
+
+	return rls.effectiveRatio()
+}
+
+func (rls *RateLimitSampler) SampleSpan(span *Span) (keep bool, ratio float64) {
+	return rls.sample()
+}
+
+func (rls *RateLimitSampler) sample() (keep bool, ratio float64) {
+	rls.Lock()
+	defer rls.Unlock()
+
+	now := rls.now()
+	if rls.last.IsZero() {
+		rls.last, rls.window = now, now
+	}
+	if elapsed := now.Sub(rls.last).Seconds(); elapsed > 0 {
+		rls.balance += elapsed * rls.maxPerSecond
+		if burst := maxFloat(rls.maxPerSecond, 1); rls.balance > burst {
+			rls.balance = burst
+		}
+		rls.last = now
+	}
+	if d := now.Sub(rls.window); d >= time.Second {
+		if d >= 2*time.Second {
+			rls.pseen, rls.pkept = 0, 0
+		} else {
+			rls.pseen, rls.pkept = rls.seen, rls.kept
+		}
+		rls.seen, rls.kept = 0, 0
+		rls.window = now
+	}
+
+	rls.seen++
+	if rls.balance >= 1 {
+		rls.balance--
+		rls.kept++
+		keep = true
+	}
+
+	return keep, rls.effectiveRatio()
+}
+
+func (rls *RateLimitSampler) effectiveRatio() float64 {
+	seen := rls.seen + rls.pseen
+	if seen == 0 {
+		return 1
+	}
+
+	return (rls.kept + rls.pkept) / seen
+}
+
+func maxFloat(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,161 @@
+package optcgo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const DefPollInterval = time.Minute
+
+// SamplingStrategy is the JSON document polled by RemoteSampler, e.g.
+//
+//	{
+//	  "default_ratio": 0.1,
+//	  "rules": [
+//	    {"service": "checkout*", "ratio": 1},
+//	    {"operation": "GET /health", "max_per_second": 1}
+//	  ]
+//	}
+type SamplingStrategy struct {
+	DefaultRatio float64        `json:"default_ratio"`
+	Rules        []SamplingRule `json:"rules"`
+}
+
+type remoteStrategy struct {
+	raw     []byte
+	sampler *RuleSampler
+}
+
+var _ SpanSampler = (*RemoteSampler)(nil)
+
+// RemoteSampler samples with the SamplingStrategy periodically fetched from
+// an http(s) URL or a local file. A new strategy replaces the current one
+// atomically, the last good strategy is kept if fetching or parsing fails.
+// Tracer closes its RemoteSampler on Close.
+type RemoteSampler struct {
+	source   string
+	interval time.Duration
+	client   *http.Client
+	strategy atomic.Value
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// NewRemoteSampler polls the strategy from source in background, right away
+// and then every interval until Close. ratio is used until a strategy is
+// loaded.
+func NewRemoteSampler(source string, ratio float64, interval time.Duration) *RemoteSampler {
+	if interval <= 0 {
+		interval = DefPollInterval
+	}
+	rs := &RemoteSampler{
+		source:   source,
+		interval: interval,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		done:     make(chan struct{}),
+	}
+	sampler, _ := NewRuleSampler(ratio)
+	rs.strategy.Store(&remoteStrategy{sampler: sampler})
+	var ctx context.Context
+	ctx, rs.cancel = context.WithCancel(context.Background())
+	go rs.poll(ctx)
+
+	return rs
+}
+
+func (rs *RemoteSampler) Sample(id uint64, ratio float64) bool {
+	return rs.current().Sample(id, ratio)
+}
+
+func (rs *RemoteSampler) Ratio() float64 {
+	return rs.current().Ratio()
+}
+
+func (rs *RemoteSampler) SampleSpan(span *Span) (keep bool, ratio float64) {
+	return rs.current().SampleSpan(span)
+}
+
+// Close stops polling and aborts the running fetch, the current strategy
+// stays in use.
+func (rs *RemoteSampler) Close() {
+	rs.cancel()
+	<-rs.done
+}
+
+func (rs *RemoteSampler) current() *RuleSampler {
+	return rs.strategy.Load().(*remoteStrategy).sampler
+}
+
+func (rs *RemoteSampler) poll(ctx context.Context) {
+	defer close(rs.done)
+
+	if err := rs.refresh(ctx); err != nil && ctx.Err() == nil {
+		fmt.Println(err.Error())
+	}
+	ticker := time.NewTicker(rs.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := rs.refresh(ctx); err != nil && ctx.Err() == nil {
+				fmt.Println(err.Error())
+			}
+		}
+	}
+}
+
+// refresh loads the strategy and swaps it in if it changed, rate limiters
+// of an unchanged strategy keep their state.
+func (rs *RemoteSampler) refresh(ctx context.Context) error {
+	raw, err := rs.fetch(ctx)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(raw, rs.strategy.Load().(*remoteStrategy).raw) {
+		return nil
+	}
+
+	strategy := &SamplingStrategy{}
+	if err = json.Unmarshal(raw, strategy); err != nil {
+		return fmt.Errorf("parse sampling strategy failed: %w", err)
+	}
+	sampler, err := NewRuleSampler(strategy.DefaultRatio, strategy.Rules...)
+	if err != nil {
+		return fmt.Errorf("compile sampling strategy failed: %w", err)
+	}
+	rs.strategy.Store(&remoteStrategy{raw: raw, sampler: sampler})
+
+	return nil
+}
+
+func (rs *RemoteSampler) fetch(ctx context.Context) ([]byte, error) {
+	if !strings.HasPrefix(rs.source, "http://") && !strings.HasPrefix(rs.source, "https://") {
+		return os.ReadFile(strings.TrimPrefix(rs.source, "file://"))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rs.source, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := rs.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch sampling strategy failed with status: %s", resp.Status)
+	}
+
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,113 @@
+package optcgo
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitRatio waits for the first strategy of rs to be loaded in background.
+func waitRatio(t *testing.T, rs *RemoteSampler, ratio float64) {
+	t.Helper()
+
+	for deadline := time.Now().Add(time.Second); rs.Ratio() != ratio; time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected ratio %f to be loaded, got %f", ratio, rs.Ratio())
+		}
+	}
+}
+
+func TestRemoteSampler(t *testing.T) {
+	var (
+		doc    atomic.Value
+		status int32 = http.StatusOK
+	)
+	doc.Store(`{"default_ratio": 0, "rules": [{"service": "checkout", "ratio": 1}]}`)
+	svr := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if s := int(atomic.LoadInt32(&status)); s != http.StatusOK {
+			resp.WriteHeader(s)
+
+			return
+		}
+		resp.Write([]byte(doc.Load().(string)))
+	}))
+	defer svr.Close()
+
+	rs := NewRemoteSampler(svr.URL, 0.5, time.Hour)
+	defer rs.Close()
+	waitRatio(t, rs, 0)
+
+	if keep, ratio := rs.SampleSpan(&Span{Service: "checkout", TraceID: 1}); !keep || ratio != 1 {
+		t.Errorf("expected checkout kept with ratio 1, got %v %f", keep, ratio)
+	}
+	if keep, ratio := rs.SampleSpan(&Span{Service: "user", TraceID: 1}); keep || ratio != 0 {
+		t.Errorf("expected user blocked with ratio 0, got %v %f", keep, ratio)
+	}
+
+	doc.Store(`{"default_ratio": 1}`)
+	if err := rs.refresh(context.Background()); err != nil {
+		t.Fatal(err.Error())
+	}
+	if r := rs.Ratio(); r != 1 {
+		t.Errorf("expected reloaded default ratio 1, got %f", r)
+	}
+
+	atomic.StoreInt32(&status, http.StatusInternalServerError)
+	if err := rs.refresh(context.Background()); err == nil {
+		t.Error("expected error on failed fetch")
+	}
+	atomic.StoreInt32(&status, http.StatusOK)
+	doc.Store(`{"default_ratio": "broken"`)
+	if err := rs.refresh(context.Background()); err == nil {
+		t.Error("expected error on malformed strategy")
+	}
+	if r := rs.Ratio(); r != 1 {
+		t.Errorf("expected last good ratio 1 to be kept, got %f", r)
+	}
+}
+
+func TestRemoteSamplerFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sampling.json")
+	if err := os.WriteFile(path, []byte(`{"default_ratio": 0.25}`), 0o644); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	rs := NewRemoteSampler("file://"+path, 1, time.Hour)
+	defer rs.Close()
+	waitRatio(t, rs, 0.25)
+}
+
+func TestRemoteSamplerClose(t *testing.T) {
+	release := make(chan struct{})
+	svr := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		select {
+		case <-release:
+		case <-req.Context().Done():
+		}
+	}))
+	defer svr.Close()
+	defer close(release)
+
+	start := time.Now()
+	rs := NewRemoteSampler(svr.URL, 0.5, time.Hour)
+	tracer := NewTracer("test_service", WithSampler(rs))
+	if r := rs.Ratio(); r != 0.5 {
+		t.Errorf("expected initial ratio 0.5 before loading, got %f", r)
+	}
+
+	// Close aborts the pending fetch and stops polling
+	tracer.Close()
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("expected no blocking on fetch, took %s", d)
+	}
+	select {
+	case <-rs.done:
+	default:
+		t.Error("expected polling stopped by tracer Close")
+	}
+}
@@ -0,0 +1,141 @@
+package optcgo
+
+import (
+	"regexp"
+	"strings"
+)
+
+// SamplingRule samples the root spans it matches with Ratio, or keeps at
+// most MaxPerSecond of them per second if MaxPerSecond is positive. Service,
+// Operation and the values of Tags are glob patterns where '*' matches any
+// sequence of characters and '?' matches a single character, empty patterns
+// match everything. OperationRegexp, if set, is matched against the
+// operation name in addition to Operation.
+type SamplingRule struct {
+	Service         string            `json:"service"`
+	Operation       string            `json:"operation"`
+	OperationRegexp string            `json:"operation_regexp"`
+	Tags            map[string]string `json:"tags"`
+	Ratio           float64           `json:"ratio"`
+	MaxPerSecond    float64           `json:"max_per_second"`
+}
+
+type samplingRule struct {
+	SamplingRule
+	service   *regexp.Regexp
+	operation *regexp.Regexp
+	opregexp  *regexp.Regexp
+	tags      map[string]*regexp.Regexp
+	limiter   *RateLimitSampler
+}
+
+func compileSamplingRule(rule SamplingRule) (*samplingRule, error) {
+	var (
+		r   = &samplingRule{SamplingRule: rule}
+		err error
+	)
+	if rule.MaxPerSecond > 0 {
+		r.limiter = NewRateLimitSampler(rule.MaxPerSecond)
+	}
+	if r.service, err = compileGlob(rule.Service); err != nil {
+		return nil, err
+	}
+	if r.operation, err = compileGlob(rule.Operation); err != nil {
+		return nil, err
+	}
+	if rule.OperationRegexp != "" {
+		if r.opregexp, err = regexp.Compile(rule.OperationRegexp); err != nil {
+			return nil, err
+		}
+	}
+	if len(rule.Tags) != 0 {
+		r.tags = make(map[string]*regexp.Regexp, len(rule.Tags))
+		for k, v := range rule.Tags {
+			if r.tags[k], err = compileGlob(v); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return r, nil
+}
+
+func (r *samplingRule) match(span *Span) bool {
+	if r.service != nil && !r.service.MatchString(span.Service) {
+		return false
+	}
+	if r.operation != nil && !r.operation.MatchString(span.Operation) {
+		return false
+	}
+	if r.opregexp != nil && !r.opregexp.MatchString(span.Operation) {
+		return false
+	}
+	for k, glob := range r.tags {
+		v, ok := span.tag(k)
+		if !ok || !glob.MatchString(v) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// compileGlob converts glob pattern into an anchored regexp, nil is
+// returned for empty pattern.
+func compileGlob(glob string) (*regexp.Regexp, error) {
+	if glob == "" {
+		return nil, nil
+	}
+
+	expr := regexp.QuoteMeta(glob)
+	expr = strings.ReplaceAll(expr, `\*`, ".*")
+	expr = strings.ReplaceAll(expr, `\?`, ".")
+
+	return regexp.Compile("^" + expr + "$")
+}
+
+var _ SpanSampler = (*RuleSampler)(nil)
+
+// RuleSampler samples root spans with the ratio of the first matching
+// SamplingRule, or with the default ratio if no rule matches.
+type RuleSampler struct {
+	rules []*samplingRule
+	ratio float64
+}
+
+func NewRuleSampler(ratio float64, rules ...SamplingRule) (*RuleSampler, error) {
+	rs := &RuleSampler{ratio: ratio}
+	for _, rule := range rules {
+		r, err := compileSamplingRule(rule)
+		if err != nil {
+			return nil, err
+		}
+		rs.rules = append(rs.rules, r)
+	}
+
+	return rs, nil
+}
+
+func (rs *RuleSampler) Sample(id uint64, ratio float64) bool {
+	return CommonSampler(ratio).Sample(id, ratio)
+}
+
+// Ratio returns the default ratio.
+func (rs *RuleSampler) Ratio() float64 {
+	return rs.ratio
+}
+
+func (rs *RuleSampler) SampleSpan(span *Span) (keep bool, ratio float64) {
+	ratio = rs.ratio
+	for _, r := range rs.rules {
+		if r.match(span) {
+			if r.limiter != nil {
+				return r.limiter.SampleSpan(span)
+			}
+			ratio = r.Ratio
+			break
+		}
+	}
+
+	return rs.Sample(uint64(span.TraceID), ratio), ratio
+}
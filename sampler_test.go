@@ -0,0 +1,291 @@
+package optcgo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+func TestRuleSampler(t *testing.T) {
+	sampler, err := NewRuleSampler(0.5,
+		SamplingRule{Service: "checkout*", Ratio: 1},
+		SamplingRule{OperationRegexp: `^GET /health(z)?$`, Ratio: 0},
+		SamplingRule{Operation: "batch.*", Tags: map[string]string{"tenant": "test-?"}, Ratio: 0},
+	)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	for _, c := range []struct {
+		service, operation string
+		tags               opentracing.Tags
+		priority           SamplePriority
+		ratio              float64
+	}{
+		{service: "checkout-api", operation: "GET /healthz", priority: SamplePriority_SamplerKeep, ratio: 1},
+		{service: "user-api", operation: "GET /healthz", priority: SamplePriority_SamplerBlock, ratio: 0},
+		{service: "user-api", operation: "batch.import", tags: opentracing.Tags{"tenant": "test-1"}, priority: SamplePriority_SamplerBlock, ratio: 0},
+		{service: "user-api", operation: "batch.import", tags: opentracing.Tags{"tenant": "acme"}, ratio: 0.5},
+	} {
+		tracer := NewTracer(c.service, WithSampler(sampler))
+		spctx := tracer.StartSpan(c.operation, c.tags).Context().(*SpanContext)
+		if spctx.SampleRatio != c.ratio {
+			t.Errorf("%s %s: expected ratio %f, got %f", c.service, c.operation, c.ratio, spctx.SampleRatio)
+		}
+		if c.ratio != 0.5 && spctx.SamplePriority != c.priority {
+			t.Errorf("%s %s: expected priority %s, got %s", c.service, c.operation, c.priority, spctx.SamplePriority)
+		}
+	}
+
+	if _, err = NewRuleSampler(1, SamplingRule{OperationRegexp: "("}); err == nil {
+		t.Error("expected error for invalid regexp")
+	}
+}
+
+func TestRateLimitSampler(t *testing.T) {
+	now := time.Now()
+	sampler := NewRateLimitSampler(10)
+	sampler.now = func() time.Time { return now }
+
+	var kept int
+	for i := 0; i < 100; i++ {
+		if keep, _ := sampler.SampleSpan(&Span{}); keep {
+			kept++
+		}
+	}
+	if kept != 10 {
+		t.Errorf("expected 10 kept spans in one second, got %d", kept)
+	}
+	if r := sampler.Ratio(); r != 0.1 {
+		t.Errorf("expected effective ratio 0.1, got %f", r)
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	kept = 0
+	for i := 0; i < 100; i++ {
+		if sampler.Sample(0, 0) {
+			kept++
+		}
+	}
+	if kept != 5 {
+		t.Errorf("expected 5 kept spans in half a second, got %d", kept)
+	}
+
+	rules, err := NewRuleSampler(1, SamplingRule{Operation: "GET /health", MaxPerSecond: 1})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	tracer := NewTracer("test_service", WithSampler(rules))
+	if p := tracer.StartSpan("GET /health").Context().(*SpanContext).SamplePriority; p != SamplePriority_SamplerKeep {
+		t.Errorf("expected first span kept, got %s", p)
+	}
+	spctx := tracer.StartSpan("GET /health").Context().(*SpanContext)
+	if spctx.SamplePriority != SamplePriority_SamplerBlock || spctx.SampleRatio != 0.5 {
+		t.Errorf("expected second span blocked with ratio 0.5, got %s %f", spctx.SamplePriority, spctx.SampleRatio)
+	}
+}
+
+func TestSamplingDecisionApplied(t *testing.T) {
+	tracer, exporter := startTestTracer(t, WithSampleRatio(0))
+
+	root := tracer.StartSpan("root")
+	child := tracer.StartSpan("child", opentracing.ChildOf(root.Context()))
+	if p := child.Context().(*SpanContext).SamplePriority; p != SamplePriority_SamplerBlock {
+		t.Errorf("expected child to inherit SamplerBlock, got %s", p)
+	}
+	child.Finish()
+	root.Finish()
+
+	// upstream keep decision wins over the local sampler
+	carrier := opentracing.TextMapCarrier{TraceParentHeader: "00-00000000000000000000000000001234-0000000000005678-01"}
+	remote, err := tracer.Extract(opentracing.TextMap, carrier)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	server := tracer.StartSpan("server", opentracing.ChildOf(remote))
+	server.Finish()
+	tracer.Close()
+
+	spans := exporter.Spans()
+	if len(spans) != 1 || spans[0].Operation != "server" {
+		t.Fatalf("expected only the upstream kept span to be exported, got %v", spans)
+	}
+}
+
+func TestUserForcedSampling(t *testing.T) {
+	tracer, exporter := startTestTracer(t, WithSampleRatio(0))
+
+	// forced at start overrides the sampler
+	kept := tracer.StartSpan("kept", UserKeep())
+	kept.Finish()
+
+	// forced after start covers the spans of the trace started before
+	root := tracer.StartSpan("root")
+	sibling := tracer.StartSpan("sibling", opentracing.ChildOf(root.Context()))
+	child := tracer.StartSpan("child", opentracing.ChildOf(root.Context()))
+	SetUserKeep(child)
+	carrier := opentracing.TextMapCarrier{}
+	if err := tracer.Inject(sibling.Context(), opentracing.TextMap, carrier); err != nil {
+		t.Fatal(err.Error())
+	}
+	sibling.Finish()
+	child.Finish()
+	root.Finish()
+
+	remote, err := tracer.Extract(opentracing.TextMap, carrier)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if p := remote.(*remoteSpanContext).SamplePriority; p != SamplePriority_UserKeep {
+		t.Errorf("expected forced decision to be propagated, got %s", p)
+	}
+
+	blocked := tracer.StartSpan("blocked", opentracing.ChildOf(remote), opentracing.Tag{Key: SamplingPriorityTag, Value: 0})
+	blocked.Finish()
+	tracer.Close()
+
+	ops := make(map[string]bool)
+	for _, sp := range exporter.Spans() {
+		ops[sp.Operation] = true
+	}
+	for op, keep := range map[string]bool{"kept": true, "root": true, "sibling": true, "child": true, "blocked": false} {
+		if ops[op] != keep {
+			t.Errorf("span %s: expected exported %v, got %v", op, keep, ops[op])
+		}
+	}
+}
+
+func TestInheritedUserSamplingNotRecorded(t *testing.T) {
+	tracer := NewTracer("test_service", WithPropagator(B3Propagator{}))
+	countForced := func() (n int) {
+		tracer.forced.Range(func(key, value interface{}) bool {
+			n++
+
+			return true
+		})
+
+		return n
+	}
+
+	remote, err := tracer.Extract(opentracing.TextMap, opentracing.TextMapCarrier{
+		B3TraceIDHeader: "0000000000001234",
+		B3SpanIDHeader:  "0000000000005678",
+		B3FlagsHeader:   "1",
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	server := tracer.StartSpan("server", opentracing.ChildOf(remote))
+	local := tracer.StartSpan("local", opentracing.ChildOf(server.Context()))
+	if p := local.Context().(*SpanContext).SamplePriority; p != SamplePriority_UserKeep {
+		t.Errorf("expected inherited user decision, got %s", p)
+	}
+	if n := countForced(); n != 0 {
+		t.Errorf("expected inherited decisions not recorded, got %d", n)
+	}
+
+	SetUserBlock(local)
+	if n := countForced(); n != 1 {
+		t.Errorf("expected decision made on live span recorded, got %d", n)
+	}
+}
+
+func TestAdaptiveSampler(t *testing.T) {
+	now := time.Now()
+	sampler := NewAdaptiveSampler(10, 0.001, time.Second)
+	sampler.now = func() time.Time { return now }
+
+	for w := 0; w < 10; w++ {
+		for i := 0; i < 1000; i++ {
+			sampler.SampleSpan(&Span{Operation: "GET /health", TraceID: int64(i + 1)})
+		}
+		sampler.SampleSpan(&Span{Operation: "POST /checkout", TraceID: 1})
+		now = now.Add(time.Second)
+	}
+
+	if _, ratio := sampler.SampleSpan(&Span{Operation: "POST /checkout", TraceID: 1}); ratio != 1 {
+		t.Errorf("expected rare operation to be fully kept, got ratio %f", ratio)
+	}
+	_, ratio := sampler.SampleSpan(&Span{Operation: "GET /health", TraceID: 1})
+	if ratio < 0.008 || ratio > 0.01 {
+		t.Errorf("expected busy operation ratio close to 0.009, got %f", ratio)
+	}
+	if r := sampler.Ratio(); r < 0.009 || r > 0.011 {
+		t.Errorf("expected effective ratio close to 10/1001, got %f", r)
+	}
+
+	floor := NewAdaptiveSampler(1, 0.1, time.Second)
+	floor.now = func() time.Time { return now }
+	for i := 0; i < 1000; i++ {
+		floor.SampleSpan(&Span{Operation: "GET /health"})
+	}
+	now = now.Add(time.Second)
+	if _, ratio = floor.SampleSpan(&Span{Operation: "GET /health"}); ratio != 0.1 {
+		t.Errorf("expected minimum ratio 0.1, got %f", ratio)
+	}
+}
+
+func TestParentBasedSampler(t *testing.T) {
+	sampler := NewParentBasedSampler(CommonSampler(0),
+		WithRemoteParentBlock(CommonSampler(1)),
+		WithLocalParentKeep(CommonSampler(0)),
+	)
+	tracer := NewTracer("test_service", WithSampler(sampler))
+
+	root := tracer.StartSpan("root")
+	if p := root.Context().(*SpanContext).SamplePriority; p != SamplePriority_SamplerBlock {
+		t.Errorf("expected root decided by root sampler, got %s", p)
+	}
+	// local blocked parent has no sampler configured, the decision is followed
+	if p := tracer.StartSpan("child", opentracing.ChildOf(root.Context())).Context().(*SpanContext).SamplePriority; p != SamplePriority_SamplerBlock {
+		t.Errorf("expected child to follow blocked local parent, got %s", p)
+	}
+
+	for _, c := range []struct {
+		traceparent string
+		priority    SamplePriority
+	}{
+		// remote kept parent is followed
+		{traceparent: "00-00000000000000000000000000001234-0000000000005678-01", priority: SamplePriority_AutoKeep},
+		// remote blocked parent is resampled with CommonSampler(1)
+		{traceparent: "00-00000000000000000000000000001234-0000000000005678-00", priority: SamplePriority_SamplerKeep},
+	} {
+		remote, err := tracer.Extract(opentracing.TextMap, opentracing.TextMapCarrier{TraceParentHeader: c.traceparent})
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		server := tracer.StartSpan("server", opentracing.ChildOf(remote))
+		if p := server.Context().(*SpanContext).SamplePriority; p != c.priority {
+			t.Errorf("%s: expected %s, got %s", c.traceparent, c.priority, p)
+		}
+
+		// local kept parent is resampled with CommonSampler(0)
+		if c.priority == SamplePriority_SamplerKeep {
+			local := tracer.StartSpan("local", opentracing.ChildOf(server.Context()))
+			if p := local.Context().(*SpanContext).SamplePriority; p != SamplePriority_SamplerBlock {
+				t.Errorf("expected child of kept local parent to be resampled, got %s", p)
+			}
+		}
+	}
+}
+
+func TestParentBasedSamplerB3(t *testing.T) {
+	sampler := NewParentBasedSampler(CommonSampler(1), WithRemoteParentKeep(CommonSampler(0)))
+	tracer := NewTracer("test_service", WithSampler(sampler), WithPropagator(B3Propagator{}))
+
+	// the B3 Sampled flag is a user decision, it is followed without
+	// consulting the remote parent samplers
+	for sampled, priority := range map[string]SamplePriority{
+		"1": SamplePriority_UserKeep,
+		"0": SamplePriority_UserBlock,
+	} {
+		remote, err := tracer.Extract(opentracing.TextMap, opentracing.TextMapCarrier{B3SingleHeader: "0000000000001234-0000000000005678-" + sampled})
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if p := tracer.StartSpan("server", opentracing.ChildOf(remote)).Context().(*SpanContext).SamplePriority; p != priority {
+			t.Errorf("sampled %s: expected %s, got %s", sampled, priority, p)
+		}
+	}
+}
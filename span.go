@@ -2,29 +2,65 @@ package optcgo
 
 import (
 	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
 	"github.com/opentracing/opentracing-go/log"
 )
 
 var _ opentracing.Span = (*Span)(nil)
 
+// spanTracers maps every unfinished Span to the spanOwner that started it,
+// Span is a generated message so the Tracer can not be kept on it. Entries are
+// removed by Finish or expired by the Tracer, see Tracer.expireSpans.
+var spanTracers sync.Map
+
+type spanOwner struct {
+	tracer  *Tracer
+	started time.Time
+}
+
 // Sets the end timestamp and finalizes Span state.
 //
 // With the exception of calls to Context() (which are always allowed),
 // Finish() must be the last call made to any span instance, and to do
 // otherwise leads to undefined behavior.
 func (sp *Span) Finish() {
+	sp.FinishWithOptions(opentracing.FinishOptions{})
+}
+
+// FinishWithOptions is like Finish() but with explicit control over
+// timestamps and log data.
+//
+// Only the first call finishes the span, later calls are ignored.
+func (sp *Span) FinishWithOptions(opts opentracing.FinishOptions) {
+	if sp.EndTime != 0 {
+		return
+	}
+	if opts.FinishTime.IsZero() {
+		sp.EndTime = time.Now().UnixNano()
+	} else {
+		sp.EndTime = opts.FinishTime.UnixNano()
+	}
+	sp.appendLogRecords(opts.LogRecords)
+	for _, data := range opts.BulkLogData {
+		record := data.ToLogRecord()
+		sp.appendLog(record.Timestamp, record.Fields)
+	}
+
 	tcr := sp.Tracer()
+	spanTracers.Delete(sp)
 	if tcr == nil {
 		return
 	}
 	tracer, ok := tcr.(*Tracer)
-	if !ok {
+	if !ok || tracer == nil {
 		return
 	}
-	sp.EndTime = time.Now().UnixNano()
 
 	if err := tracer.finishSpan(sp); err != nil {
 		// log.Println(err.Error())
@@ -32,24 +68,26 @@ func (sp *Span) Finish() {
 	}
 }
 
-// FinishWithOptions is like Finish() but with explicit control over
-// timestamps and log data.
-func (sp *Span) FinishWithOptions(opts opentracing.FinishOptions) {}
-
 // Context() yields the SpanContext for this Span. Note that the return
 // value of Context() is still valid after a call to Span.Finish(), as is
 // a call to Span.Context() after a call to Span.Finish().
 func (sp *Span) Context() opentracing.SpanContext {
 	spctx := &SpanContext{
-		TraceID:  sp.TraceID,
-		ParentID: sp.SpanID,
-	}
-	if p, ok := sp.Metrics[SamplePriorityKey]; ok {
-		spctx.SamplePriority = SamplePriority(p.GetInt32Value())
+		TraceIDHigh: sp.TraceIDHigh,
+		TraceID:     sp.TraceID,
+		ParentID:    sp.SpanID,
+		TraceState:  sp.TraceState,
 	}
+	spctx.SamplePriority = sp.samplePriority()
 	if r, ok := sp.Metrics[SampleRatioKey]; ok {
 		spctx.SampleRatio = r.GetDoublevalue()
 	}
+	if len(sp.Baggage) != 0 {
+		spctx.Meta = make(map[string]string, len(sp.Baggage))
+		for k, v := range sp.Baggage {
+			spctx.Meta[k] = v
+		}
+	}
 
 	return spctx
 }
@@ -58,6 +96,8 @@ func (sp *Span) Context() opentracing.SpanContext {
 //
 // Returns a reference to this Span for chaining.
 func (sp *Span) SetOperationName(operationName string) opentracing.Span {
+	sp.Operation = operationName
+
 	return sp
 }
 
@@ -101,18 +141,126 @@ func (sp *Span) SetTags(tags map[string]interface{}) opentracing.Span {
 //
 // Returns a reference to this Span for chaining.
 func (sp *Span) SetTag(key string, value interface{}) opentracing.Span {
-	switch t := value.(type) {
-	case *Numeric:
-		sp.SetMetric(key, t)
+	if key == SamplingPriorityTag {
+		if p, ok := userPriority(value); ok {
+			sp.forceSampling(p)
+
+			return sp
+		}
+	}
+	if key == string(ext.Error) {
+		sp.setErrorStatus(value)
+	}
+
+	// a tag lives either in Meta or in Metrics
+	if number, ok := tagNumeric(value); ok {
+		delete(sp.Meta, key)
+		sp.SetMetric(key, number)
+	} else {
+		delete(sp.Metrics, key)
+		sp.SetMeta(key, tagString(value))
+	}
+
+	return sp
+}
+
+// tagNumeric converts numbers and bools, named types included, into Numeric.
+func tagNumeric(value interface{}) (*Numeric, bool) {
+	if number, ok := value.(*Numeric); ok {
+		return number, number != nil
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Bool:
+		return &Numeric{Numeric: &Numeric_Boolvalue{Boolvalue: v.Bool()}}, true
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return &Numeric{Numeric: &Numeric_Int32Value{Int32Value: int32(v.Int())}}, true
+	case reflect.Int, reflect.Int64:
+		return &Numeric{Numeric: &Numeric_Int64Value{Int64Value: v.Int()}}, true
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Numeric{Numeric: &Numeric_Uint32Value{Uint32Value: uint32(v.Uint())}}, true
+	case reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		return &Numeric{Numeric: &Numeric_Uint64Value{Uint64Value: v.Uint()}}, true
+	case reflect.Float32:
+		return &Numeric{Numeric: &Numeric_Floatvalue{Floatvalue: float32(v.Float())}}, true
+	case reflect.Float64:
+		return &Numeric{Numeric: &Numeric_Doublevalue{Doublevalue: v.Float()}}, true
+	default:
+		return nil, false
+	}
+}
+
+// tagString converts strings, named types included, and other values into
+// their plain string form.
+func tagString(value interface{}) string {
+	switch v := value.(type) {
 	case string:
-		sp.SetMeta(key, t)
+		return v
 	case []byte:
-		sp.SetMeta(key, string(t))
+		return string(v)
+	case fmt.Stringer:
+		return v.String()
+	}
+	if v := reflect.ValueOf(value); v.Kind() == reflect.String {
+		return v.String()
+	}
+
+	return fmt.Sprintf("%v", value)
+}
+
+// tag returns the value of tag key as string, whether it is kept in Meta or
+// in Metrics.
+func (sp *Span) tag(key string) (string, bool) {
+	if v, ok := sp.Meta[key]; ok {
+		return v, true
+	}
+	if number, ok := sp.Metrics[key]; ok {
+		return numericString(number)
+	}
+
+	return "", false
+}
+
+func numericString(number *Numeric) (string, bool) {
+	switch v := number.GetNumeric().(type) {
+	case *Numeric_Int32Value:
+		return strconv.FormatInt(int64(v.Int32Value), 10), true
+	case *Numeric_Int64Value:
+		return strconv.FormatInt(v.Int64Value, 10), true
+	case *Numeric_Uint32Value:
+		return strconv.FormatUint(uint64(v.Uint32Value), 10), true
+	case *Numeric_Uint64Value:
+		return strconv.FormatUint(v.Uint64Value, 10), true
+	case *Numeric_Floatvalue:
+		return strconv.FormatFloat(float64(v.Floatvalue), 'g', -1, 32), true
+	case *Numeric_Doublevalue:
+		return strconv.FormatFloat(v.Doublevalue, 'g', -1, 64), true
+	case *Numeric_Boolvalue:
+		return strconv.FormatBool(v.Boolvalue), true
 	default:
-		sp.SetMeta(key, fmt.Sprintf("%#v", t))
+		return "", false
 	}
+}
 
-	return sp
+// setErrorStatus maps the error tag into Status, true marks an OK span as
+// Error and false clears Error, other statuses are left as set.
+func (sp *Span) setErrorStatus(value interface{}) {
+	var failed bool
+	switch v := value.(type) {
+	case bool:
+		failed = v
+	case string:
+		failed, _ = strconv.ParseBool(v)
+	default:
+		return
+	}
+	switch {
+	case failed && sp.Status == SpanStatus_OK:
+		sp.Status = SpanStatus_Error
+	case !failed && sp.Status == SpanStatus_Error:
+		sp.Status = SpanStatus_OK
+	}
 }
 
 // LogFields is an efficient and type-checked way to record key:value
@@ -125,7 +273,9 @@ func (sp *Span) SetTag(key string, value interface{}) opentracing.Span {
 //        log.Int("waited.millis", 1500))
 //
 // Also see Span.FinishWithOptions() and FinishOptions.BulkLogData.
-func (sp *Span) LogFields(fields ...log.Field) {}
+func (sp *Span) LogFields(fields ...log.Field) {
+	sp.appendLog(time.Now(), fields)
+}
 
 // LogKV is a concise, readable way to record key:value logging data about
 // a Span, though unfortunately this also makes it less efficient and less
@@ -145,7 +295,15 @@ func (sp *Span) LogFields(fields ...log.Field) {}
 // bools, Go error instances, or arbitrary structs.
 //
 // (Note to implementors: consider the log.InterleavedKVToFields() helper)
-func (sp *Span) LogKV(alternatingKeyValues ...interface{}) {}
+func (sp *Span) LogKV(alternatingKeyValues ...interface{}) {
+	fields, err := log.InterleavedKVToFields(alternatingKeyValues...)
+	if err != nil {
+		sp.LogFields(log.Error(err), log.String("function", "LogKV"))
+
+		return
+	}
+	sp.LogFields(fields...)
+}
 
 // SetBaggageItem sets a key:value pair on this Span and its SpanContext
 // that also propagates to descendants of this Span.
@@ -164,17 +322,43 @@ func (sp *Span) LogKV(alternatingKeyValues ...interface{}) {}
 //
 // Returns a reference to this Span for chaining.
 func (sp *Span) SetBaggageItem(restrictedKey, value string) opentracing.Span {
+	var tracer *Tracer
+	if t, ok := sp.Tracer().(*Tracer); ok {
+		tracer = t
+	}
+	if sp.Baggage == nil {
+		sp.Baggage = make(map[string]string)
+	}
+	maxItems, maxBytes := tracer.baggageLimits()
+	setBaggageItem(sp.Baggage, restrictedKey, value, maxItems, maxBytes)
+
 	return sp
 }
 
 // Gets the value for a baggage item given its key. Returns the empty string
 // if the value isn't found in this Span.
 func (sp *Span) BaggageItem(restrictedKey string) string {
-	return ""
+	return sp.Baggage[restrictedKey]
+}
+
+// forceSampling sets the user decision on span, once the span is started the
+// decision is recorded for its whole trace.
+func (sp *Span) forceSampling(priority SamplePriority) {
+	setSampling(sp, priority, sp.Metrics[SampleRatioKey].GetDoublevalue())
+	if sp.TraceIDHigh == 0 && sp.TraceID == 0 {
+		return
+	}
+	if tracer, ok := sp.Tracer().(*Tracer); ok && tracer != nil {
+		tracer.forceSampling(sp, priority)
+	}
 }
 
 // Provides access to the Tracer that created this Span.
 func (sp *Span) Tracer() opentracing.Tracer {
+	if owner, ok := spanTracers.Load(sp); ok {
+		return owner.(spanOwner).tracer
+	}
+
 	gtracer := opentracing.GlobalTracer()
 	if gtracer != nil {
 		if t, ok := gtracer.(*Tracer); ok {
@@ -186,10 +370,17 @@ func (sp *Span) Tracer() opentracing.Tracer {
 }
 
 // Deprecated: use LogFields or LogKV
-func (sp *Span) LogEvent(event string) {}
+func (sp *Span) LogEvent(event string) {
+	sp.LogFields(log.String("event", event))
+}
 
 // Deprecated: use LogFields or LogKV
-func (sp *Span) LogEventWithPayload(event string, payload interface{}) {}
+func (sp *Span) LogEventWithPayload(event string, payload interface{}) {
+	sp.LogFields(log.String("event", event), log.Object("payload", payload))
+}
 
 // Deprecated: use LogFields or LogKV
-func (sp *Span) Log(data opentracing.LogData) {}
+func (sp *Span) Log(data opentracing.LogData) {
+	record := data.ToLogRecord()
+	sp.appendLog(record.Timestamp, record.Fields)
+}
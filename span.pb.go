@@ -130,6 +130,52 @@ func (SamplePriority) EnumDescriptor() ([]byte, []int) {
 	return file_span_proto_rawDescGZIP(), []int{1}
 }
 
+type SpanReferenceType int32
+
+const (
+	SpanReferenceType_ChildOf     SpanReferenceType = 0
+	SpanReferenceType_FollowsFrom SpanReferenceType = 1
+)
+
+// Enum value maps for SpanReferenceType.
+var (
+	SpanReferenceType_name = map[int32]string{
+		0: "ChildOf",
+		1: "FollowsFrom",
+	}
+	SpanReferenceType_value = map[string]int32{
+		"ChildOf":     0,
+		"FollowsFrom": 1,
+	}
+)
+
+func (x SpanReferenceType) Enum() *SpanReferenceType {
+	p := new(SpanReferenceType)
+	*p = x
+	return p
+}
+
+func (x SpanReferenceType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SpanReferenceType) Descriptor() protoreflect.EnumDescriptor {
+	return file_span_proto_enumTypes[2].Descriptor()
+}
+
+func (SpanReferenceType) Type() protoreflect.EnumType {
+	return &file_span_proto_enumTypes[2]
+}
+
+func (x SpanReferenceType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SpanReferenceType.Descriptor instead.
+func (SpanReferenceType) EnumDescriptor() ([]byte, []int) {
+	return file_span_proto_rawDescGZIP(), []int{2}
+}
+
 type Numeric struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -142,6 +188,7 @@ type Numeric struct {
 	//	*Numeric_Uint64Value
 	//	*Numeric_Floatvalue
 	//	*Numeric_Doublevalue
+	//	*Numeric_Boolvalue
 	Numeric isNumeric_Numeric `protobuf_oneof:"Numeric"`
 }
 
@@ -226,6 +273,13 @@ func (x *Numeric) GetDoublevalue() float64 {
 	return 0
 }
 
+func (x *Numeric) GetBoolvalue() bool {
+	if x, ok := x.GetNumeric().(*Numeric_Boolvalue); ok {
+		return x.Boolvalue
+	}
+	return false
+}
+
 type isNumeric_Numeric interface {
 	isNumeric_Numeric()
 }
@@ -254,6 +308,10 @@ type Numeric_Doublevalue struct {
 	Doublevalue float64 `protobuf:"fixed64,6,opt,name=doublevalue,proto3,oneof"`
 }
 
+type Numeric_Boolvalue struct {
+	Boolvalue bool `protobuf:"varint,7,opt,name=boolvalue,proto3,oneof"`
+}
+
 func (*Numeric_Int32Value) isNumeric_Numeric() {}
 
 func (*Numeric_Int64Value) isNumeric_Numeric() {}
@@ -266,27 +324,319 @@ func (*Numeric_Floatvalue) isNumeric_Numeric() {}
 
 func (*Numeric_Doublevalue) isNumeric_Numeric() {}
 
+func (*Numeric_Boolvalue) isNumeric_Numeric() {}
+
+type LogField struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Key string `protobuf:"bytes,1,opt,name=Key,proto3" json:"Key,omitempty"`
+	// Types that are assignable to Value:
+	//	*LogField_Stringvalue
+	//	*LogField_Boolvalue
+	//	*LogField_Int64Value
+	//	*LogField_Uint64Value
+	//	*LogField_Doublevalue
+	//	*LogField_Errorvalue
+	//	*LogField_Objectvalue
+	Value isLogField_Value `protobuf_oneof:"Value"`
+}
+
+func (x *LogField) Reset() {
+	*x = LogField{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_span_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LogField) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogField) ProtoMessage() {}
+
+func (x *LogField) ProtoReflect() protoreflect.Message {
+	mi := &file_span_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogField.ProtoReflect.Descriptor instead.
+func (*LogField) Descriptor() ([]byte, []int) {
+	return file_span_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *LogField) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (m *LogField) GetValue() isLogField_Value {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (x *LogField) GetStringvalue() string {
+	if x, ok := x.GetValue().(*LogField_Stringvalue); ok {
+		return x.Stringvalue
+	}
+	return ""
+}
+
+func (x *LogField) GetBoolvalue() bool {
+	if x, ok := x.GetValue().(*LogField_Boolvalue); ok {
+		return x.Boolvalue
+	}
+	return false
+}
+
+func (x *LogField) GetInt64Value() int64 {
+	if x, ok := x.GetValue().(*LogField_Int64Value); ok {
+		return x.Int64Value
+	}
+	return 0
+}
+
+func (x *LogField) GetUint64Value() uint64 {
+	if x, ok := x.GetValue().(*LogField_Uint64Value); ok {
+		return x.Uint64Value
+	}
+	return 0
+}
+
+func (x *LogField) GetDoublevalue() float64 {
+	if x, ok := x.GetValue().(*LogField_Doublevalue); ok {
+		return x.Doublevalue
+	}
+	return 0
+}
+
+func (x *LogField) GetErrorvalue() string {
+	if x, ok := x.GetValue().(*LogField_Errorvalue); ok {
+		return x.Errorvalue
+	}
+	return ""
+}
+
+func (x *LogField) GetObjectvalue() string {
+	if x, ok := x.GetValue().(*LogField_Objectvalue); ok {
+		return x.Objectvalue
+	}
+	return ""
+}
+
+type isLogField_Value interface {
+	isLogField_Value()
+}
+
+type LogField_Stringvalue struct {
+	Stringvalue string `protobuf:"bytes,2,opt,name=stringvalue,proto3,oneof"`
+}
+
+type LogField_Boolvalue struct {
+	Boolvalue bool `protobuf:"varint,3,opt,name=boolvalue,proto3,oneof"`
+}
+
+type LogField_Int64Value struct {
+	Int64Value int64 `protobuf:"varint,4,opt,name=int64value,proto3,oneof"`
+}
+
+type LogField_Uint64Value struct {
+	Uint64Value uint64 `protobuf:"varint,5,opt,name=uint64value,proto3,oneof"`
+}
+
+type LogField_Doublevalue struct {
+	Doublevalue float64 `protobuf:"fixed64,6,opt,name=doublevalue,proto3,oneof"`
+}
+
+type LogField_Errorvalue struct {
+	Errorvalue string `protobuf:"bytes,7,opt,name=errorvalue,proto3,oneof"`
+}
+
+type LogField_Objectvalue struct {
+	Objectvalue string `protobuf:"bytes,8,opt,name=objectvalue,proto3,oneof"`
+}
+
+func (*LogField_Stringvalue) isLogField_Value() {}
+
+func (*LogField_Boolvalue) isLogField_Value() {}
+
+func (*LogField_Int64Value) isLogField_Value() {}
+
+func (*LogField_Uint64Value) isLogField_Value() {}
+
+func (*LogField_Doublevalue) isLogField_Value() {}
+
+func (*LogField_Errorvalue) isLogField_Value() {}
+
+func (*LogField_Objectvalue) isLogField_Value() {}
+
+type SpanLog struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Timestamp int64       `protobuf:"varint,1,opt,name=Timestamp,proto3" json:"Timestamp,omitempty"`
+	Fields    []*LogField `protobuf:"bytes,2,rep,name=Fields,proto3" json:"Fields,omitempty"`
+}
+
+func (x *SpanLog) Reset() {
+	*x = SpanLog{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_span_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SpanLog) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpanLog) ProtoMessage() {}
+
+func (x *SpanLog) ProtoReflect() protoreflect.Message {
+	mi := &file_span_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpanLog.ProtoReflect.Descriptor instead.
+func (*SpanLog) Descriptor() ([]byte, []int) {
+	return file_span_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SpanLog) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *SpanLog) GetFields() []*LogField {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+type SpanLink struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type        SpanReferenceType `protobuf:"varint,1,opt,name=Type,proto3,enum=opentracing.go.SpanReferenceType" json:"Type,omitempty"`
+	TraceID     int64             `protobuf:"varint,2,opt,name=TraceID,proto3" json:"TraceID,omitempty"`
+	SpanID      int64             `protobuf:"varint,3,opt,name=SpanID,proto3" json:"SpanID,omitempty"`
+	TraceIDHigh int64             `protobuf:"varint,4,opt,name=TraceIDHigh,proto3" json:"TraceIDHigh,omitempty"`
+}
+
+func (x *SpanLink) Reset() {
+	*x = SpanLink{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_span_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SpanLink) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpanLink) ProtoMessage() {}
+
+func (x *SpanLink) ProtoReflect() protoreflect.Message {
+	mi := &file_span_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpanLink.ProtoReflect.Descriptor instead.
+func (*SpanLink) Descriptor() ([]byte, []int) {
+	return file_span_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SpanLink) GetType() SpanReferenceType {
+	if x != nil {
+		return x.Type
+	}
+	return SpanReferenceType_ChildOf
+}
+
+func (x *SpanLink) GetTraceID() int64 {
+	if x != nil {
+		return x.TraceID
+	}
+	return 0
+}
+
+func (x *SpanLink) GetSpanID() int64 {
+	if x != nil {
+		return x.SpanID
+	}
+	return 0
+}
+
+func (x *SpanLink) GetTraceIDHigh() int64 {
+	if x != nil {
+		return x.TraceIDHigh
+	}
+	return 0
+}
+
 type Span struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	TraceID   int64               `protobuf:"varint,1,opt,name=TraceID,proto3" json:"TraceID,omitempty"`
-	ParentID  int64               `protobuf:"varint,2,opt,name=ParentID,proto3" json:"ParentID,omitempty"`
-	SpanID    int64               `protobuf:"varint,3,opt,name=SpanID,proto3" json:"SpanID,omitempty"`
-	Service   string              `protobuf:"bytes,4,opt,name=Service,proto3" json:"Service,omitempty"`
-	Operation string              `protobuf:"bytes,5,opt,name=Operation,proto3" json:"Operation,omitempty"`
-	Meta      map[string]string   `protobuf:"bytes,7,rep,name=Meta,proto3" json:"Meta,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	Metrics   map[string]*Numeric `protobuf:"bytes,8,rep,name=Metrics,proto3" json:"Metrics,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	Status    SpanStatus          `protobuf:"varint,9,opt,name=Status,proto3,enum=opentracing.go.SpanStatus" json:"Status,omitempty"`
-	StartTime int64               `protobuf:"varint,10,opt,name=StartTime,proto3" json:"StartTime,omitempty"`
-	EndTime   int64               `protobuf:"varint,11,opt,name=EndTime,proto3" json:"EndTime,omitempty"`
+	TraceID     int64               `protobuf:"varint,1,opt,name=TraceID,proto3" json:"TraceID,omitempty"`
+	ParentID    int64               `protobuf:"varint,2,opt,name=ParentID,proto3" json:"ParentID,omitempty"`
+	SpanID      int64               `protobuf:"varint,3,opt,name=SpanID,proto3" json:"SpanID,omitempty"`
+	Service     string              `protobuf:"bytes,4,opt,name=Service,proto3" json:"Service,omitempty"`
+	Operation   string              `protobuf:"bytes,5,opt,name=Operation,proto3" json:"Operation,omitempty"`
+	Meta        map[string]string   `protobuf:"bytes,7,rep,name=Meta,proto3" json:"Meta,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Metrics     map[string]*Numeric `protobuf:"bytes,8,rep,name=Metrics,proto3" json:"Metrics,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Status      SpanStatus          `protobuf:"varint,9,opt,name=Status,proto3,enum=opentracing.go.SpanStatus" json:"Status,omitempty"`
+	StartTime   int64               `protobuf:"varint,10,opt,name=StartTime,proto3" json:"StartTime,omitempty"`
+	EndTime     int64               `protobuf:"varint,11,opt,name=EndTime,proto3" json:"EndTime,omitempty"`
+	Baggage     map[string]string   `protobuf:"bytes,12,rep,name=Baggage,proto3" json:"Baggage,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Logs        []*SpanLog          `protobuf:"bytes,13,rep,name=Logs,proto3" json:"Logs,omitempty"`
+	Links       []*SpanLink         `protobuf:"bytes,14,rep,name=Links,proto3" json:"Links,omitempty"`
+	TraceIDHigh int64               `protobuf:"varint,15,opt,name=TraceIDHigh,proto3" json:"TraceIDHigh,omitempty"`
+	// tracestate members of other vendors
+	TraceState string `protobuf:"bytes,16,opt,name=TraceState,proto3" json:"TraceState,omitempty"`
 }
 
 func (x *Span) Reset() {
 	*x = Span{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_span_proto_msgTypes[1]
+		mi := &file_span_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -299,7 +649,7 @@ func (x *Span) String() string {
 func (*Span) ProtoMessage() {}
 
 func (x *Span) ProtoReflect() protoreflect.Message {
-	mi := &file_span_proto_msgTypes[1]
+	mi := &file_span_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -312,7 +662,7 @@ func (x *Span) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Span.ProtoReflect.Descriptor instead.
 func (*Span) Descriptor() ([]byte, []int) {
-	return file_span_proto_rawDescGZIP(), []int{1}
+	return file_span_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *Span) GetTraceID() int64 {
@@ -385,6 +735,41 @@ func (x *Span) GetEndTime() int64 {
 	return 0
 }
 
+func (x *Span) GetBaggage() map[string]string {
+	if x != nil {
+		return x.Baggage
+	}
+	return nil
+}
+
+func (x *Span) GetLogs() []*SpanLog {
+	if x != nil {
+		return x.Logs
+	}
+	return nil
+}
+
+func (x *Span) GetLinks() []*SpanLink {
+	if x != nil {
+		return x.Links
+	}
+	return nil
+}
+
+func (x *Span) GetTraceIDHigh() int64 {
+	if x != nil {
+		return x.TraceIDHigh
+	}
+	return 0
+}
+
+func (x *Span) GetTraceState() string {
+	if x != nil {
+		return x.TraceState
+	}
+	return ""
+}
+
 type Trace struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -396,7 +781,7 @@ type Trace struct {
 func (x *Trace) Reset() {
 	*x = Trace{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_span_proto_msgTypes[2]
+		mi := &file_span_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -409,7 +794,7 @@ func (x *Trace) String() string {
 func (*Trace) ProtoMessage() {}
 
 func (x *Trace) ProtoReflect() protoreflect.Message {
-	mi := &file_span_proto_msgTypes[2]
+	mi := &file_span_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -422,7 +807,7 @@ func (x *Trace) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Trace.ProtoReflect.Descriptor instead.
 func (*Trace) Descriptor() ([]byte, []int) {
-	return file_span_proto_rawDescGZIP(), []int{2}
+	return file_span_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *Trace) GetTrace() []*Span {
@@ -443,7 +828,7 @@ type Traces struct {
 func (x *Traces) Reset() {
 	*x = Traces{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_span_proto_msgTypes[3]
+		mi := &file_span_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -456,7 +841,7 @@ func (x *Traces) String() string {
 func (*Traces) ProtoMessage() {}
 
 func (x *Traces) ProtoReflect() protoreflect.Message {
-	mi := &file_span_proto_msgTypes[3]
+	mi := &file_span_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -469,7 +854,7 @@ func (x *Traces) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Traces.ProtoReflect.Descriptor instead.
 func (*Traces) Descriptor() ([]byte, []int) {
-	return file_span_proto_rawDescGZIP(), []int{3}
+	return file_span_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *Traces) GetTraces() []*Trace {
@@ -483,7 +868,7 @@ var File_span_proto protoreflect.FileDescriptor
 
 var file_span_proto_rawDesc = []byte{
 	0x0a, 0x0a, 0x73, 0x70, 0x61, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0e, 0x6f, 0x70,
-	0x65, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x69, 0x6e, 0x67, 0x2e, 0x67, 0x6f, 0x22, 0xe6, 0x01, 0x0a,
+	0x65, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x69, 0x6e, 0x67, 0x2e, 0x67, 0x6f, 0x22, 0x86, 0x02, 0x0a,
 	0x07, 0x4e, 0x75, 0x6d, 0x65, 0x72, 0x69, 0x63, 0x12, 0x20, 0x0a, 0x0a, 0x69, 0x6e, 0x74, 0x33,
 	0x32, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x0a,
 	0x69, 0x6e, 0x74, 0x33, 0x32, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x20, 0x0a, 0x0a, 0x69, 0x6e,
@@ -497,60 +882,116 @@ var file_span_proto_rawDesc = []byte{
 	0x75, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x02, 0x48, 0x00, 0x52, 0x0a, 0x66, 0x6c, 0x6f, 0x61,
 	0x74, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x22, 0x0a, 0x0b, 0x64, 0x6f, 0x75, 0x62, 0x6c, 0x65,
 	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x48, 0x00, 0x52, 0x0b, 0x64,
-	0x6f, 0x75, 0x62, 0x6c, 0x65, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x42, 0x09, 0x0a, 0x07, 0x4e, 0x75,
-	0x6d, 0x65, 0x72, 0x69, 0x63, 0x22, 0xf7, 0x03, 0x0a, 0x04, 0x53, 0x70, 0x61, 0x6e, 0x12, 0x18,
-	0x0a, 0x07, 0x54, 0x72, 0x61, 0x63, 0x65, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52,
-	0x07, 0x54, 0x72, 0x61, 0x63, 0x65, 0x49, 0x44, 0x12, 0x1a, 0x0a, 0x08, 0x50, 0x61, 0x72, 0x65,
-	0x6e, 0x74, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x50, 0x61, 0x72, 0x65,
-	0x6e, 0x74, 0x49, 0x44, 0x12, 0x16, 0x0a, 0x06, 0x53, 0x70, 0x61, 0x6e, 0x49, 0x44, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x53, 0x70, 0x61, 0x6e, 0x49, 0x44, 0x12, 0x18, 0x0a, 0x07,
-	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x53,
-	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74,
-	0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x4f, 0x70, 0x65, 0x72, 0x61,
-	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x32, 0x0a, 0x04, 0x4d, 0x65, 0x74, 0x61, 0x18, 0x07, 0x20, 0x03,
-	0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x6f, 0x70, 0x65, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x69, 0x6e, 0x67,
-	0x2e, 0x67, 0x6f, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x45, 0x6e, 0x74,
-	0x72, 0x79, 0x52, 0x04, 0x4d, 0x65, 0x74, 0x61, 0x12, 0x3b, 0x0a, 0x07, 0x4d, 0x65, 0x74, 0x72,
-	0x69, 0x63, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x6f, 0x70, 0x65, 0x6e,
-	0x74, 0x72, 0x61, 0x63, 0x69, 0x6e, 0x67, 0x2e, 0x67, 0x6f, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x2e,
-	0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x4d, 0x65,
-	0x74, 0x72, 0x69, 0x63, 0x73, 0x12, 0x32, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18,
-	0x09, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x6f, 0x70, 0x65, 0x6e, 0x74, 0x72, 0x61, 0x63,
-	0x69, 0x6e, 0x67, 0x2e, 0x67, 0x6f, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75,
-	0x73, 0x52, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x53, 0x74, 0x61,
-	0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x53, 0x74,
-	0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x45, 0x6e, 0x64, 0x54, 0x69,
-	0x6d, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x45, 0x6e, 0x64, 0x54, 0x69, 0x6d,
-	0x65, 0x1a, 0x37, 0x0a, 0x09, 0x4d, 0x65, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10,
-	0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79,
-	0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x53, 0x0a, 0x0c, 0x4d, 0x65,
-	0x74, 0x72, 0x69, 0x63, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
-	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2d, 0x0a, 0x05,
-	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6f, 0x70,
-	0x65, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x69, 0x6e, 0x67, 0x2e, 0x67, 0x6f, 0x2e, 0x4e, 0x75, 0x6d,
-	0x65, 0x72, 0x69, 0x63, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22,
-	0x33, 0x0a, 0x05, 0x54, 0x72, 0x61, 0x63, 0x65, 0x12, 0x2a, 0x0a, 0x05, 0x54, 0x72, 0x61, 0x63,
-	0x65, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6f, 0x70, 0x65, 0x6e, 0x74, 0x72,
-	0x61, 0x63, 0x69, 0x6e, 0x67, 0x2e, 0x67, 0x6f, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x52, 0x05, 0x54,
-	0x72, 0x61, 0x63, 0x65, 0x22, 0x37, 0x0a, 0x06, 0x54, 0x72, 0x61, 0x63, 0x65, 0x73, 0x12, 0x2d,
-	0x0a, 0x06, 0x54, 0x72, 0x61, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15,
+	0x6f, 0x75, 0x62, 0x6c, 0x65, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x1e, 0x0a, 0x09, 0x62, 0x6f,
+	0x6f, 0x6c, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52,
+	0x09, 0x62, 0x6f, 0x6f, 0x6c, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x42, 0x09, 0x0a, 0x07, 0x4e, 0x75,
+	0x6d, 0x65, 0x72, 0x69, 0x63, 0x22, 0x99, 0x02, 0x0a, 0x08, 0x4c, 0x6f, 0x67, 0x46, 0x69, 0x65,
+	0x6c, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x4b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x4b, 0x65, 0x79, 0x12, 0x22, 0x0a, 0x0b, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0b, 0x73, 0x74, 0x72,
+	0x69, 0x6e, 0x67, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x1e, 0x0a, 0x09, 0x62, 0x6f, 0x6f, 0x6c,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x09, 0x62,
+	0x6f, 0x6f, 0x6c, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x20, 0x0a, 0x0a, 0x69, 0x6e, 0x74, 0x36,
+	0x34, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x0a,
+	0x69, 0x6e, 0x74, 0x36, 0x34, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x22, 0x0a, 0x0b, 0x75, 0x69,
+	0x6e, 0x74, 0x36, 0x34, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x48,
+	0x00, 0x52, 0x0b, 0x75, 0x69, 0x6e, 0x74, 0x36, 0x34, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x22,
+	0x0a, 0x0b, 0x64, 0x6f, 0x75, 0x62, 0x6c, 0x65, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x01, 0x48, 0x00, 0x52, 0x0b, 0x64, 0x6f, 0x75, 0x62, 0x6c, 0x65, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x12, 0x20, 0x0a, 0x0a, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0a, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x12, 0x22, 0x0a, 0x0b, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0b, 0x6f, 0x62, 0x6a,
+	0x65, 0x63, 0x74, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x42, 0x07, 0x0a, 0x05, 0x56, 0x61, 0x6c, 0x75,
+	0x65, 0x22, 0x59, 0x0a, 0x07, 0x53, 0x70, 0x61, 0x6e, 0x4c, 0x6f, 0x67, 0x12, 0x1c, 0x0a, 0x09,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x09, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x30, 0x0a, 0x06, 0x46, 0x69,
+	0x65, 0x6c, 0x64, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x6f, 0x70, 0x65,
+	0x6e, 0x74, 0x72, 0x61, 0x63, 0x69, 0x6e, 0x67, 0x2e, 0x67, 0x6f, 0x2e, 0x4c, 0x6f, 0x67, 0x46,
+	0x69, 0x65, 0x6c, 0x64, 0x52, 0x06, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x22, 0x95, 0x01, 0x0a,
+	0x08, 0x53, 0x70, 0x61, 0x6e, 0x4c, 0x69, 0x6e, 0x6b, 0x12, 0x35, 0x0a, 0x04, 0x54, 0x79, 0x70,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x21, 0x2e, 0x6f, 0x70, 0x65, 0x6e, 0x74, 0x72,
+	0x61, 0x63, 0x69, 0x6e, 0x67, 0x2e, 0x67, 0x6f, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x52, 0x65, 0x66,
+	0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x54, 0x79, 0x70, 0x65,
+	0x12, 0x18, 0x0a, 0x07, 0x54, 0x72, 0x61, 0x63, 0x65, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x07, 0x54, 0x72, 0x61, 0x63, 0x65, 0x49, 0x44, 0x12, 0x16, 0x0a, 0x06, 0x53, 0x70,
+	0x61, 0x6e, 0x49, 0x44, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x53, 0x70, 0x61, 0x6e,
+	0x49, 0x44, 0x12, 0x20, 0x0a, 0x0b, 0x54, 0x72, 0x61, 0x63, 0x65, 0x49, 0x44, 0x48, 0x69, 0x67,
+	0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x54, 0x72, 0x61, 0x63, 0x65, 0x49, 0x44,
+	0x48, 0x69, 0x67, 0x68, 0x22, 0x8f, 0x06, 0x0a, 0x04, 0x53, 0x70, 0x61, 0x6e, 0x12, 0x18, 0x0a,
+	0x07, 0x54, 0x72, 0x61, 0x63, 0x65, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07,
+	0x54, 0x72, 0x61, 0x63, 0x65, 0x49, 0x44, 0x12, 0x1a, 0x0a, 0x08, 0x50, 0x61, 0x72, 0x65, 0x6e,
+	0x74, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x50, 0x61, 0x72, 0x65, 0x6e,
+	0x74, 0x49, 0x44, 0x12, 0x16, 0x0a, 0x06, 0x53, 0x70, 0x61, 0x6e, 0x49, 0x44, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x06, 0x53, 0x70, 0x61, 0x6e, 0x49, 0x44, 0x12, 0x18, 0x0a, 0x07, 0x53,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x53, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x32, 0x0a, 0x04, 0x4d, 0x65, 0x74, 0x61, 0x18, 0x07, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x1e, 0x2e, 0x6f, 0x70, 0x65, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x69, 0x6e, 0x67, 0x2e,
+	0x67, 0x6f, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x52, 0x04, 0x4d, 0x65, 0x74, 0x61, 0x12, 0x3b, 0x0a, 0x07, 0x4d, 0x65, 0x74, 0x72, 0x69,
+	0x63, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x6f, 0x70, 0x65, 0x6e, 0x74,
+	0x72, 0x61, 0x63, 0x69, 0x6e, 0x67, 0x2e, 0x67, 0x6f, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x2e, 0x4d,
+	0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x4d, 0x65, 0x74,
+	0x72, 0x69, 0x63, 0x73, 0x12, 0x32, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x09,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x6f, 0x70, 0x65, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x69,
+	0x6e, 0x67, 0x2e, 0x67, 0x6f, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x52, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x53, 0x74, 0x61, 0x72,
+	0x74, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x53, 0x74, 0x61,
+	0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x45, 0x6e, 0x64, 0x54, 0x69, 0x6d,
+	0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x45, 0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65,
+	0x12, 0x3b, 0x0a, 0x07, 0x42, 0x61, 0x67, 0x67, 0x61, 0x67, 0x65, 0x18, 0x0c, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x21, 0x2e, 0x6f, 0x70, 0x65, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x69, 0x6e, 0x67, 0x2e,
+	0x67, 0x6f, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x2e, 0x42, 0x61, 0x67, 0x67, 0x61, 0x67, 0x65, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x42, 0x61, 0x67, 0x67, 0x61, 0x67, 0x65, 0x12, 0x2b, 0x0a,
+	0x04, 0x4c, 0x6f, 0x67, 0x73, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6f, 0x70,
+	0x65, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x69, 0x6e, 0x67, 0x2e, 0x67, 0x6f, 0x2e, 0x53, 0x70, 0x61,
+	0x6e, 0x4c, 0x6f, 0x67, 0x52, 0x04, 0x4c, 0x6f, 0x67, 0x73, 0x12, 0x2e, 0x0a, 0x05, 0x4c, 0x69,
+	0x6e, 0x6b, 0x73, 0x18, 0x0e, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x6f, 0x70, 0x65, 0x6e,
+	0x74, 0x72, 0x61, 0x63, 0x69, 0x6e, 0x67, 0x2e, 0x67, 0x6f, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x4c,
+	0x69, 0x6e, 0x6b, 0x52, 0x05, 0x4c, 0x69, 0x6e, 0x6b, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x54, 0x72,
+	0x61, 0x63, 0x65, 0x49, 0x44, 0x48, 0x69, 0x67, 0x68, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x0b, 0x54, 0x72, 0x61, 0x63, 0x65, 0x49, 0x44, 0x48, 0x69, 0x67, 0x68, 0x12, 0x1e, 0x0a, 0x0a,
+	0x54, 0x72, 0x61, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x18, 0x10, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x54, 0x72, 0x61, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x1a, 0x37, 0x0a, 0x09,
+	0x4d, 0x65, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x53, 0x0a, 0x0c, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2d, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6f, 0x70, 0x65, 0x6e, 0x74, 0x72, 0x61,
+	0x63, 0x69, 0x6e, 0x67, 0x2e, 0x67, 0x6f, 0x2e, 0x4e, 0x75, 0x6d, 0x65, 0x72, 0x69, 0x63, 0x52,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x3a, 0x0a, 0x0c, 0x42, 0x61,
+	0x67, 0x67, 0x61, 0x67, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x33, 0x0a, 0x05, 0x54, 0x72, 0x61, 0x63, 0x65, 0x12,
+	0x2a, 0x0a, 0x05, 0x54, 0x72, 0x61, 0x63, 0x65, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14,
 	0x2e, 0x6f, 0x70, 0x65, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x69, 0x6e, 0x67, 0x2e, 0x67, 0x6f, 0x2e,
-	0x54, 0x72, 0x61, 0x63, 0x65, 0x52, 0x06, 0x54, 0x72, 0x61, 0x63, 0x65, 0x73, 0x2a, 0x39, 0x0a,
-	0x0a, 0x53, 0x70, 0x61, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x06, 0x0a, 0x02, 0x4f,
-	0x4b, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x10, 0x01, 0x12, 0x0c,
-	0x0a, 0x08, 0x52, 0x65, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x10, 0x02, 0x12, 0x0a, 0x0a, 0x06,
-	0x43, 0x72, 0x69, 0x73, 0x69, 0x73, 0x10, 0x03, 0x2a, 0x6d, 0x0a, 0x0e, 0x53, 0x61, 0x6d, 0x70,
-	0x6c, 0x65, 0x50, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x12, 0x0c, 0x0a, 0x08, 0x41, 0x75,
-	0x74, 0x6f, 0x4b, 0x65, 0x65, 0x70, 0x10, 0x00, 0x12, 0x0d, 0x0a, 0x09, 0x41, 0x75, 0x74, 0x6f,
-	0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x10, 0x01, 0x12, 0x0f, 0x0a, 0x0b, 0x53, 0x61, 0x6d, 0x70, 0x6c,
-	0x65, 0x72, 0x4b, 0x65, 0x65, 0x70, 0x10, 0x02, 0x12, 0x10, 0x0a, 0x0c, 0x53, 0x61, 0x6d, 0x70,
-	0x6c, 0x65, 0x72, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x10, 0x03, 0x12, 0x0c, 0x0a, 0x08, 0x55, 0x73,
-	0x65, 0x72, 0x4b, 0x65, 0x65, 0x70, 0x10, 0x04, 0x12, 0x0d, 0x0a, 0x09, 0x55, 0x73, 0x65, 0x72,
-	0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x10, 0x05, 0x42, 0x2e, 0x5a, 0x2c, 0x67, 0x69, 0x74, 0x68, 0x75,
-	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x43, 0x6f, 0x64, 0x61, 0x70, 0x65, 0x57, 0x69, 0x6c, 0x64,
-	0x2f, 0x6f, 0x70, 0x65, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x69, 0x6e, 0x67, 0x2d, 0x67, 0x6f, 0x2f,
-	0x3b, 0x6f, 0x70, 0x74, 0x63, 0x67, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x53, 0x70, 0x61, 0x6e, 0x52, 0x05, 0x54, 0x72, 0x61, 0x63, 0x65, 0x22, 0x37, 0x0a, 0x06, 0x54,
+	0x72, 0x61, 0x63, 0x65, 0x73, 0x12, 0x2d, 0x0a, 0x06, 0x54, 0x72, 0x61, 0x63, 0x65, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x6f, 0x70, 0x65, 0x6e, 0x74, 0x72, 0x61, 0x63,
+	0x69, 0x6e, 0x67, 0x2e, 0x67, 0x6f, 0x2e, 0x54, 0x72, 0x61, 0x63, 0x65, 0x52, 0x06, 0x54, 0x72,
+	0x61, 0x63, 0x65, 0x73, 0x2a, 0x39, 0x0a, 0x0a, 0x53, 0x70, 0x61, 0x6e, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x12, 0x06, 0x0a, 0x02, 0x4f, 0x4b, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x45, 0x72,
+	0x72, 0x6f, 0x72, 0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08, 0x52, 0x65, 0x63, 0x6f, 0x76, 0x65, 0x72,
+	0x79, 0x10, 0x02, 0x12, 0x0a, 0x0a, 0x06, 0x43, 0x72, 0x69, 0x73, 0x69, 0x73, 0x10, 0x03, 0x2a,
+	0x6d, 0x0a, 0x0e, 0x53, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x50, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74,
+	0x79, 0x12, 0x0c, 0x0a, 0x08, 0x41, 0x75, 0x74, 0x6f, 0x4b, 0x65, 0x65, 0x70, 0x10, 0x00, 0x12,
+	0x0d, 0x0a, 0x09, 0x41, 0x75, 0x74, 0x6f, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x10, 0x01, 0x12, 0x0f,
+	0x0a, 0x0b, 0x53, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x72, 0x4b, 0x65, 0x65, 0x70, 0x10, 0x02, 0x12,
+	0x10, 0x0a, 0x0c, 0x53, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x72, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x10,
+	0x03, 0x12, 0x0c, 0x0a, 0x08, 0x55, 0x73, 0x65, 0x72, 0x4b, 0x65, 0x65, 0x70, 0x10, 0x04, 0x12,
+	0x0d, 0x0a, 0x09, 0x55, 0x73, 0x65, 0x72, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x10, 0x05, 0x2a, 0x31,
+	0x0a, 0x11, 0x53, 0x70, 0x61, 0x6e, 0x52, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x54,
+	0x79, 0x70, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x43, 0x68, 0x69, 0x6c, 0x64, 0x4f, 0x66, 0x10, 0x00,
+	0x12, 0x0f, 0x0a, 0x0b, 0x46, 0x6f, 0x6c, 0x6c, 0x6f, 0x77, 0x73, 0x46, 0x72, 0x6f, 0x6d, 0x10,
+	0x01, 0x42, 0x2e, 0x5a, 0x2c, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
+	0x43, 0x6f, 0x64, 0x61, 0x70, 0x65, 0x57, 0x69, 0x6c, 0x64, 0x2f, 0x6f, 0x70, 0x65, 0x6e, 0x74,
+	0x72, 0x61, 0x63, 0x69, 0x6e, 0x67, 0x2d, 0x67, 0x6f, 0x2f, 0x3b, 0x6f, 0x70, 0x74, 0x63, 0x67,
+	0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -565,30 +1006,40 @@ func file_span_proto_rawDescGZIP() []byte {
 	return file_span_proto_rawDescData
 }
 
-var file_span_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_span_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_span_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_span_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
 var file_span_proto_goTypes = []interface{}{
-	(SpanStatus)(0),     // 0: opentracing.go.SpanStatus
-	(SamplePriority)(0), // 1: opentracing.go.SamplePriority
-	(*Numeric)(nil),     // 2: opentracing.go.Numeric
-	(*Span)(nil),        // 3: opentracing.go.Span
-	(*Trace)(nil),       // 4: opentracing.go.Trace
-	(*Traces)(nil),      // 5: opentracing.go.Traces
-	nil,                 // 6: opentracing.go.Span.MetaEntry
-	nil,                 // 7: opentracing.go.Span.MetricsEntry
+	(SpanStatus)(0),        // 0: opentracing.go.SpanStatus
+	(SamplePriority)(0),    // 1: opentracing.go.SamplePriority
+	(SpanReferenceType)(0), // 2: opentracing.go.SpanReferenceType
+	(*Numeric)(nil),        // 3: opentracing.go.Numeric
+	(*LogField)(nil),       // 4: opentracing.go.LogField
+	(*SpanLog)(nil),        // 5: opentracing.go.SpanLog
+	(*SpanLink)(nil),       // 6: opentracing.go.SpanLink
+	(*Span)(nil),           // 7: opentracing.go.Span
+	(*Trace)(nil),          // 8: opentracing.go.Trace
+	(*Traces)(nil),         // 9: opentracing.go.Traces
+	nil,                    // 10: opentracing.go.Span.MetaEntry
+	nil,                    // 11: opentracing.go.Span.MetricsEntry
+	nil,                    // 12: opentracing.go.Span.BaggageEntry
 }
 var file_span_proto_depIdxs = []int32{
-	6, // 0: opentracing.go.Span.Meta:type_name -> opentracing.go.Span.MetaEntry
-	7, // 1: opentracing.go.Span.Metrics:type_name -> opentracing.go.Span.MetricsEntry
-	0, // 2: opentracing.go.Span.Status:type_name -> opentracing.go.SpanStatus
-	3, // 3: opentracing.go.Trace.Trace:type_name -> opentracing.go.Span
-	4, // 4: opentracing.go.Traces.Traces:type_name -> opentracing.go.Trace
-	2, // 5: opentracing.go.Span.MetricsEntry.value:type_name -> opentracing.go.Numeric
-	6, // [6:6] is the sub-list for method output_type
-	6, // [6:6] is the sub-list for method input_type
-	6, // [6:6] is the sub-list for extension type_name
-	6, // [6:6] is the sub-list for extension extendee
-	0, // [0:6] is the sub-list for field type_name
+	4,  // 0: opentracing.go.SpanLog.Fields:type_name -> opentracing.go.LogField
+	2,  // 1: opentracing.go.SpanLink.Type:type_name -> opentracing.go.SpanReferenceType
+	10, // 2: opentracing.go.Span.Meta:type_name -> opentracing.go.Span.MetaEntry
+	11, // 3: opentracing.go.Span.Metrics:type_name -> opentracing.go.Span.MetricsEntry
+	0,  // 4: opentracing.go.Span.Status:type_name -> opentracing.go.SpanStatus
+	12, // 5: opentracing.go.Span.Baggage:type_name -> opentracing.go.Span.BaggageEntry
+	5,  // 6: opentracing.go.Span.Logs:type_name -> opentracing.go.SpanLog
+	6,  // 7: opentracing.go.Span.Links:type_name -> opentracing.go.SpanLink
+	7,  // 8: opentracing.go.Trace.Trace:type_name -> opentracing.go.Span
+	8,  // 9: opentracing.go.Traces.Traces:type_name -> opentracing.go.Trace
+	3,  // 10: opentracing.go.Span.MetricsEntry.value:type_name -> opentracing.go.Numeric
+	11, // [11:11] is the sub-list for method output_type
+	11, // [11:11] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
 }
 
 func init() { file_span_proto_init() }
@@ -610,7 +1061,7 @@ func file_span_proto_init() {
 			}
 		}
 		file_span_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Span); i {
+			switch v := v.(*LogField); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -622,7 +1073,7 @@ func file_span_proto_init() {
 			}
 		}
 		file_span_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Trace); i {
+			switch v := v.(*SpanLog); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -634,6 +1085,42 @@ func file_span_proto_init() {
 			}
 		}
 		file_span_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SpanLink); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_span_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Span); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_span_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Trace); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_span_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Traces); i {
 			case 0:
 				return &v.state
@@ -653,14 +1140,24 @@ func file_span_proto_init() {
 		(*Numeric_Uint64Value)(nil),
 		(*Numeric_Floatvalue)(nil),
 		(*Numeric_Doublevalue)(nil),
+		(*Numeric_Boolvalue)(nil),
+	}
+	file_span_proto_msgTypes[1].OneofWrappers = []interface{}{
+		(*LogField_Stringvalue)(nil),
+		(*LogField_Boolvalue)(nil),
+		(*LogField_Int64Value)(nil),
+		(*LogField_Uint64Value)(nil),
+		(*LogField_Doublevalue)(nil),
+		(*LogField_Errorvalue)(nil),
+		(*LogField_Objectvalue)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_span_proto_rawDesc,
-			NumEnums:      2,
-			NumMessages:   6,
+			NumEnums:      3,
+			NumMessages:   10,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
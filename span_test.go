@@ -0,0 +1,93 @@
+package optcgo
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/opentracing/opentracing-go/log"
+)
+
+func TestSpanLogFields(t *testing.T) {
+	sp := &Span{}
+	sp.LogFields(
+		log.String("event", "soft error"),
+		log.Bool("retry", true),
+		log.Int("waited.millis", 1500),
+		log.Uint64("bytes", 1<<63),
+		log.Float32("ratio", 0.5),
+		log.Error(errors.New("cache timeout")),
+		log.Object("payload", []int{1}),
+	)
+	sp.LogKV("event", "done")
+
+	if len(sp.Logs) != 2 {
+		t.Fatalf("expected 2 logs, got %d", len(sp.Logs))
+	}
+	fields := sp.Logs[0].Fields
+	if len(fields) != 7 {
+		t.Fatalf("expected 7 fields, got %d", len(fields))
+	}
+	if v := fields[0].GetStringvalue(); v != "soft error" {
+		t.Errorf("unexpected string field %q", v)
+	}
+	if v := fields[1].GetBoolvalue(); !v {
+		t.Error("unexpected bool field false")
+	}
+	if v := fields[2].GetInt64Value(); v != 1500 {
+		t.Errorf("unexpected int field %d", v)
+	}
+	if v := fields[3].GetUint64Value(); v != 1<<63 {
+		t.Errorf("unexpected uint field %d", v)
+	}
+	if v := fields[4].GetDoublevalue(); v != 0.5 {
+		t.Errorf("unexpected float field %f", v)
+	}
+	if v := fields[5].GetErrorvalue(); fields[5].Key != "error.object" || v != "cache timeout" {
+		t.Errorf("unexpected error field %s: %q", fields[5].Key, v)
+	}
+	if v := fields[6].GetObjectvalue(); v != "[]int{1}" {
+		t.Errorf("unexpected object field %q", v)
+	}
+	if sp.Logs[0].Timestamp == 0 {
+		t.Error("log timestamp not set")
+	}
+}
+
+func TestSpanSetTagExt(t *testing.T) {
+	sp := &Span{}
+	ext.SpanKindRPCServer.Set(sp)
+	ext.Component.Set(sp, "grpc")
+	ext.HTTPStatusCode.Set(sp, 500)
+	ext.PeerPort.Set(sp, 8080)
+	ext.Error.Set(sp, true)
+	sp.SetTag("retries", 3)
+	sp.SetTag("ratio", 0.25)
+
+	if v := sp.Meta[string(ext.SpanKind)]; v != "server" {
+		t.Errorf("unexpected span kind %q", v)
+	}
+	if v := sp.Meta[string(ext.Component)]; v != "grpc" {
+		t.Errorf("unexpected component %q", v)
+	}
+	if v := sp.Metrics[string(ext.HTTPStatusCode)].GetUint32Value(); v != 500 {
+		t.Errorf("unexpected http status code %d", v)
+	}
+	if v := sp.Metrics[string(ext.PeerPort)].GetUint32Value(); v != 8080 {
+		t.Errorf("unexpected peer port %d", v)
+	}
+	if v := sp.Metrics[string(ext.Error)]; !v.GetBoolvalue() || sp.Status != SpanStatus_Error {
+		t.Errorf("unexpected error tag %v or status %s", v, sp.Status)
+	}
+	if v := sp.Metrics["retries"].GetInt64Value(); v != 3 {
+		t.Errorf("unexpected int tag %d", v)
+	}
+	if v := sp.Metrics["ratio"].GetDoublevalue(); v != 0.25 {
+		t.Errorf("unexpected float tag %f", v)
+	}
+
+	sp.SetTag("retries", "none")
+	if _, ok := sp.Metrics["retries"]; ok || sp.Meta["retries"] != "none" {
+		t.Error("overwritten tag kept in Metrics")
+	}
+}
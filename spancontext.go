@@ -1,12 +1,39 @@
 package optcgo
 
-import "context"
+import (
+	"context"
+
+	"github.com/opentracing/opentracing-go"
+)
 
 type spctxkey struct{}
 
+// remoteSpanContext is the SpanContext returned by Extract. Being remote is
+// process local so it is kept apart from the SpanContext message.
+type remoteSpanContext struct {
+	*SpanContext
+}
+
+// spanContextOf returns the SpanContext of this package held by sm, and
+// whether it was extracted from a remote process.
+func spanContextOf(sm opentracing.SpanContext) (spctx *SpanContext, remote bool) {
+	switch t := sm.(type) {
+	case *SpanContext:
+		return t, false
+	case *remoteSpanContext:
+		if t != nil {
+			return t.SpanContext, t.SpanContext != nil
+		}
+	}
+
+	return nil, false
+}
+
 func (spctx *SpanContext) ForeachBaggageItem(handler func(k, v string) bool) {
 	for k, v := range spctx.Meta {
-		handler(k, v)
+		if !handler(k, v) {
+			return
+		}
 	}
 }
 
@@ -0,0 +1,90 @@
+package optcgo
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/log"
+)
+
+var _ log.Encoder = (*logFieldEncoder)(nil)
+
+// logFieldEncoder converts opentracing log.Field into typed LogField.
+type logFieldEncoder struct {
+	fields []*LogField
+}
+
+func (enc *logFieldEncoder) emit(key string, value isLogField_Value) {
+	enc.fields = append(enc.fields, &LogField{Key: key, Value: value})
+}
+
+func (enc *logFieldEncoder) EmitString(key, value string) {
+	enc.emit(key, &LogField_Stringvalue{Stringvalue: value})
+}
+
+func (enc *logFieldEncoder) EmitBool(key string, value bool) {
+	enc.emit(key, &LogField_Boolvalue{Boolvalue: value})
+}
+
+func (enc *logFieldEncoder) EmitInt(key string, value int) {
+	enc.emit(key, &LogField_Int64Value{Int64Value: int64(value)})
+}
+
+func (enc *logFieldEncoder) EmitInt32(key string, value int32) {
+	enc.emit(key, &LogField_Int64Value{Int64Value: int64(value)})
+}
+
+func (enc *logFieldEncoder) EmitInt64(key string, value int64) {
+	enc.emit(key, &LogField_Int64Value{Int64Value: value})
+}
+
+func (enc *logFieldEncoder) EmitUint32(key string, value uint32) {
+	enc.emit(key, &LogField_Uint64Value{Uint64Value: uint64(value)})
+}
+
+func (enc *logFieldEncoder) EmitUint64(key string, value uint64) {
+	enc.emit(key, &LogField_Uint64Value{Uint64Value: value})
+}
+
+func (enc *logFieldEncoder) EmitFloat32(key string, value float32) {
+	enc.emit(key, &LogField_Doublevalue{Doublevalue: float64(value)})
+}
+
+func (enc *logFieldEncoder) EmitFloat64(key string, value float64) {
+	enc.emit(key, &LogField_Doublevalue{Doublevalue: value})
+}
+
+func (enc *logFieldEncoder) EmitObject(key string, value interface{}) {
+	enc.emit(key, &LogField_Objectvalue{Objectvalue: fmt.Sprintf("%#v", value)})
+}
+
+func (enc *logFieldEncoder) EmitLazyLogger(value log.LazyLogger) {
+	value(enc)
+}
+
+func (enc *logFieldEncoder) encode(fields []log.Field) {
+	for _, field := range fields {
+		// log.Field marshals errors as plain strings
+		if err, ok := field.Value().(error); ok {
+			enc.emit(field.Key(), &LogField_Errorvalue{Errorvalue: err.Error()})
+		} else {
+			field.Marshal(enc)
+		}
+	}
+}
+
+func (sp *Span) appendLog(timestamp time.Time, fields []log.Field) {
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+	enc := &logFieldEncoder{}
+	enc.encode(fields)
+	sp.Logs = append(sp.Logs, &SpanLog{Timestamp: timestamp.UnixNano(), Fields: enc.fields})
+}
+
+func (sp *Span) appendLogRecords(records []opentracing.LogRecord) {
+	for _, record := range records {
+		sp.appendLog(record.Timestamp, record.Fields)
+	}
+}
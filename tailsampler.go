@@ -0,0 +1,266 @@
+package optcgo
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	DefDecisionWait = 10 * time.Second
+	DefMaxTraces    = 10000
+	DefMaxSpans     = 100000
+)
+
+// TailPolicy decides whether to keep a trace given all of its spans buffered
+// during the decision window.
+type TailPolicy func(spans []*Span) bool
+
+// StatusPolicy keeps traces having any span with one of statuses.
+func StatusPolicy(statuses ...SpanStatus) TailPolicy {
+	return func(spans []*Span) bool {
+		for _, sp := range spans {
+			for _, status := range statuses {
+				if sp.Status == status {
+					return true
+				}
+			}
+		}
+
+		return false
+	}
+}
+
+// SampledPolicy keeps traces kept by the head Sampler, it keeps the head
+// sampled traces as baseline for traces not matched by other policies.
+func SampledPolicy() TailPolicy {
+	return func(spans []*Span) bool {
+		for _, sp := range spans {
+			if sp.samplePriority() == SamplePriority_SamplerKeep {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// LatencyPolicy keeps traces having any span lasting longer than threshold.
+func LatencyPolicy(threshold time.Duration) TailPolicy {
+	return func(spans []*Span) bool {
+		for _, sp := range spans {
+			if time.Duration(sp.EndTime-sp.StartTime) > threshold {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// TagPolicy keeps traces having any span tagged with key:value.
+func TagPolicy(key, value string) TailPolicy {
+	return func(spans []*Span) bool {
+		for _, sp := range spans {
+			if v, ok := sp.tag(key); ok && v == value {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// RatioPolicy keeps the given ratio of traces, it is the baseline policy
+// for traces not matched by other policies.
+func RatioPolicy(ratio float64) TailPolicy {
+	return func(spans []*Span) bool {
+		return len(spans) != 0 && CommonSampler(ratio).Sample(uint64(spans[0].TraceID), ratio)
+	}
+}
+
+// TailSamplingConfig configures TailSampler. Spans are buffered per trace
+// for DecisionWait after the first span of the trace arrives, then the trace
+// is kept if any of Policies matches. When more than MaxTraces traces or
+// MaxSpans spans are buffered the oldest traces are decided early.
+//
+// With tail sampling the spans blocked by the head Sampler of Tracer are not
+// dropped but left to Policies, add SampledPolicy to keep the head sampled
+// traces as well. Traces kept by user are kept regardless of Policies.
+type TailSamplingConfig struct {
+	DecisionWait time.Duration
+	MaxTraces    int
+	MaxSpans     int
+	Policies     []TailPolicy
+}
+
+// WithTailSampling puts a TailSampler in front of the Exporter of Tracer.
+func WithTailSampling(config TailSamplingConfig) StartTracerOption {
+	return func(tracer *Tracer) {
+		tracer.tailSampling = &config
+	}
+}
+
+type traceKey struct {
+	high, low int64
+}
+
+type pendingTrace struct {
+	key     traceKey
+	arrival time.Time
+	spans   []*Span
+	elem    *list.Element
+}
+
+type traceDecision struct {
+	keep bool
+	at   time.Time
+}
+
+var _ Exporter = (*TailSampler)(nil)
+
+// TailSampler is an Exporter buffering spans per trace and exporting to the
+// next Exporter only the traces kept by its policies. Spans arriving after
+// the decision of their trace follow that decision.
+//
+// Traces whose decision window is over are decided when Export is called.
+// Tracer set up WithTailSampling calls it on every flush, with or without
+// spans, so the next Exporter is only called from the flush loop.
+type TailSampler struct {
+	sync.Mutex
+	next      Exporter
+	wait      time.Duration
+	maxTraces int
+	maxSpans  int
+	policies  []TailPolicy
+	pending   map[traceKey]*pendingTrace
+	order     *list.List
+	spans     int
+	decided   map[traceKey]traceDecision
+}
+
+func NewTailSampler(next Exporter, config TailSamplingConfig) *TailSampler {
+	if config.DecisionWait <= 0 {
+		config.DecisionWait = DefDecisionWait
+	}
+	if config.MaxTraces <= 0 {
+		config.MaxTraces = DefMaxTraces
+	}
+	if config.MaxSpans <= 0 {
+		config.MaxSpans = DefMaxSpans
+	}
+	ts := &TailSampler{
+		next:      next,
+		wait:      config.DecisionWait,
+		maxTraces: config.MaxTraces,
+		maxSpans:  config.MaxSpans,
+		policies:  config.Policies,
+		pending:   make(map[traceKey]*pendingTrace),
+		order:     list.New(),
+		decided:   make(map[traceKey]traceDecision),
+	}
+
+	return ts
+}
+
+func (ts *TailSampler) Export(ctx context.Context, trace *Trace) error {
+	ts.Lock()
+	now := time.Now()
+	kept := ts.expire(now)
+	for _, sp := range trace.Trace {
+		key := traceKey{high: sp.TraceIDHigh, low: sp.TraceID}
+		if d, ok := ts.decided[key]; ok {
+			if d.keep {
+				kept = append(kept, sp)
+			}
+			continue
+		}
+
+		pt, ok := ts.pending[key]
+		if !ok {
+			pt = &pendingTrace{key: key, arrival: now}
+			pt.elem = ts.order.PushBack(pt)
+			ts.pending[key] = pt
+		}
+		pt.spans = append(pt.spans, sp)
+		ts.spans++
+	}
+	for ts.order.Len() > ts.maxTraces || ts.spans > ts.maxSpans {
+		kept = append(kept, ts.decide(ts.order.Front().Value.(*pendingTrace), now)...)
+	}
+	ts.Unlock()
+
+	return ts.export(ctx, kept)
+}
+
+func (ts *TailSampler) Shutdown(ctx context.Context) error {
+	ts.Lock()
+	var kept []*Span
+	for ts.order.Len() != 0 {
+		kept = append(kept, ts.decide(ts.order.Front().Value.(*pendingTrace), time.Now())...)
+	}
+	ts.Unlock()
+	if err := ts.export(ctx, kept); err != nil {
+		return err
+	}
+
+	return ts.next.Shutdown(ctx)
+}
+
+// expire decides the traces whose decision window is over and forgets
+// decisions older than two decision windows, the caller must hold the lock.
+func (ts *TailSampler) expire(now time.Time) []*Span {
+	var kept []*Span
+	for e := ts.order.Front(); e != nil; e = ts.order.Front() {
+		pt := e.Value.(*pendingTrace)
+		if now.Sub(pt.arrival) < ts.wait {
+			break
+		}
+		kept = append(kept, ts.decide(pt, now)...)
+	}
+	for key, d := range ts.decided {
+		if now.Sub(d.at) >= 2*ts.wait {
+			delete(ts.decided, key)
+		}
+	}
+
+	return kept
+}
+
+// decide removes pt from pending traces and returns its spans if kept, the
+// caller must hold the lock.
+func (ts *TailSampler) decide(pt *pendingTrace, now time.Time) []*Span {
+	ts.order.Remove(pt.elem)
+	delete(ts.pending, pt.key)
+	ts.spans -= len(pt.spans)
+
+	keep := userKept(pt.spans)
+	for i := 0; !keep && i < len(ts.policies); i++ {
+		keep = ts.policies[i](pt.spans)
+	}
+	ts.decided[pt.key] = traceDecision{keep: keep, at: now}
+	if keep {
+		return pt.spans
+	}
+
+	return nil
+}
+
+func userKept(spans []*Span) bool {
+	for _, sp := range spans {
+		if sp.samplePriority() == SamplePriority_UserKeep {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (ts *TailSampler) export(ctx context.Context, spans []*Span) error {
+	if len(spans) == 0 {
+		return nil
+	}
+
+	return ts.next.Export(ctx, &Trace{Trace: spans})
+}
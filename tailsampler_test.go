@@ -0,0 +1,136 @@
+package optcgo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+)
+
+func TestTailSampler(t *testing.T) {
+	exporter := &recordExporter{}
+	ts := NewTailSampler(exporter, TailSamplingConfig{
+		DecisionWait: 50 * time.Millisecond,
+		Policies: []TailPolicy{
+			StatusPolicy(SpanStatus_Error, SpanStatus_Crisis),
+			LatencyPolicy(time.Second),
+			TagPolicy("checkout", "true"),
+			RatioPolicy(0),
+		},
+	})
+
+	ctx := context.Background()
+	ts.Export(ctx, &Trace{Trace: []*Span{
+		{TraceID: 1, SpanID: 1},
+		{TraceID: 2, SpanID: 2, EndTime: int64(2 * time.Second)},
+		{TraceID: 3, SpanID: 3},
+		{TraceID: 4, SpanID: 4, Meta: map[string]string{"checkout": "true"}},
+	}})
+	ts.Export(ctx, &Trace{Trace: []*Span{{TraceID: 1, SpanID: 5, Status: SpanStatus_Error}}})
+	if spans := exporter.Spans(); len(spans) != 0 {
+		t.Fatalf("expected spans to be buffered, got %d exported", len(spans))
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	// late span of a kept trace follows the decision
+	ts.Export(ctx, &Trace{Trace: []*Span{{TraceID: 2, SpanID: 6}, {TraceID: 3, SpanID: 7}}})
+
+	kept := make(map[int64]bool)
+	for _, sp := range exporter.Spans() {
+		kept[sp.SpanID] = true
+	}
+	for id, keep := range map[int64]bool{1: true, 2: true, 3: false, 4: true, 5: true, 6: true, 7: false} {
+		if kept[id] != keep {
+			t.Errorf("span %d: expected kept %v, got %v", id, keep, kept[id])
+		}
+	}
+
+	if err := ts.Shutdown(ctx); err != nil {
+		t.Fatal(err.Error())
+	}
+	if !exporter.shutdown {
+		t.Error("next exporter not shut down")
+	}
+}
+
+func TestTailSamplerEviction(t *testing.T) {
+	exporter := &recordExporter{}
+	ts := NewTailSampler(exporter, TailSamplingConfig{
+		DecisionWait: time.Hour,
+		MaxTraces:    1,
+		Policies:     []TailPolicy{RatioPolicy(1)},
+	})
+	defer ts.Shutdown(context.Background())
+
+	ts.Export(context.Background(), &Trace{Trace: []*Span{{TraceID: 1, SpanID: 1}, {TraceID: 2, SpanID: 2}}})
+	if spans := exporter.Spans(); len(spans) != 1 || spans[0].TraceID != 1 {
+		t.Fatalf("expected oldest trace to be decided early, got %v", spans)
+	}
+}
+
+func TestTailSamplerMaxSpans(t *testing.T) {
+	exporter := &recordExporter{}
+	ts := NewTailSampler(exporter, TailSamplingConfig{
+		DecisionWait: time.Hour,
+		MaxSpans:     2,
+		Policies:     []TailPolicy{RatioPolicy(1)},
+	})
+	defer ts.Shutdown(context.Background())
+
+	ts.Export(context.Background(), &Trace{Trace: []*Span{{TraceID: 1, SpanID: 1}, {TraceID: 1, SpanID: 2}, {TraceID: 2, SpanID: 3}}})
+	if spans := exporter.Spans(); len(spans) != 2 || spans[0].TraceID != 1 {
+		t.Fatalf("expected oldest trace to be decided early, got %v", spans)
+	}
+}
+
+func TestTailSamplingDecidedOnFlush(t *testing.T) {
+	tracer, exporter := startTestTracer(t,
+		WithFlushInterval(10*time.Millisecond),
+		WithTailSampling(TailSamplingConfig{
+			DecisionWait: 20 * time.Millisecond,
+			Policies:     []TailPolicy{RatioPolicy(1)},
+		}),
+	)
+
+	tracer.StartSpan("root").Finish()
+	deadline := time.Now().Add(time.Second)
+	for len(exporter.Spans()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if spans := exporter.Spans(); len(spans) != 1 {
+		t.Errorf("expected trace decided by flush loop, got %d spans", len(spans))
+	}
+}
+
+func TestTailSamplingWithHeadSampler(t *testing.T) {
+	tracer, exporter := startTestTracer(t,
+		WithSampleRatio(0),
+		WithTailSampling(TailSamplingConfig{
+			DecisionWait: time.Hour,
+			Policies:     []TailPolicy{StatusPolicy(SpanStatus_Error), SampledPolicy()},
+		}),
+	)
+
+	failed := tracer.StartSpan("failed")
+	ext.Error.Set(failed, true)
+	failed.Finish()
+	tracer.StartSpan("blocked").Finish()
+	tracer.StartSpan("kept", UserKeep()).Finish()
+	tracer.StartSpan("dropped", UserBlock(), opentracing.Tag{Key: string(ext.Error), Value: true}).Finish()
+	tracer.Close()
+
+	if failed.(*Span).Status != SpanStatus_Error {
+		t.Errorf("expected error tag to set status, got %s", failed.(*Span).Status)
+	}
+	ops := make(map[string]bool)
+	for _, sp := range exporter.Spans() {
+		ops[sp.Operation] = true
+	}
+	for op, keep := range map[string]bool{"failed": true, "blocked": false, "kept": true, "dropped": false} {
+		if ops[op] != keep {
+			t.Errorf("span %s: expected exported %v, got %v", op, keep, ops[op])
+		}
+	}
+}
@@ -1,25 +1,36 @@
 package optcgo
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"math/rand"
+	"io"
 	"os"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/opentracing/opentracing-go"
+	"google.golang.org/protobuf/proto"
 )
 
 const (
 	DefService       = "UniversalOpenTracingTransform"
 	DefFlushBuffer   = 1024
 	DefFlushInterval = 3 * time.Second
+	DefExportTimeout = 30 * time.Second
+	// DefMaxSpanDuration is how long a Span is tracked by its Tracer before
+	// Finish, spans finished later are reported to the global Tracer.
+	DefMaxSpanDuration = time.Hour
 )
 
 var (
 	defGlobalTracer *Tracer
+
+	errTracerClosed       = errors.New("tracer closed, span dropped")
+	errFinishedBufferFull = errors.New("finished buffer full and tracer not started, span dropped")
 )
 
 type StartTracerOption func(tracer *Tracer)
@@ -30,6 +41,12 @@ func WithSampleRatio(ratio float64) StartTracerOption {
 	}
 }
 
+func WithSampler(sampler Sampler) StartTracerOption {
+	return func(tracer *Tracer) {
+		tracer.sampler = sampler
+	}
+}
+
 func WithGlobalTags(tags map[string]interface{}) StartTracerOption {
 	return func(tracer *Tracer) {
 		if &tracer.tags == &tags {
@@ -59,6 +76,37 @@ func WithFlushInterval(d time.Duration) StartTracerOption {
 	}
 }
 
+// WithExportTimeout bounds the time given to the Exporter for every flush
+// and for its Shutdown.
+func WithExportTimeout(d time.Duration) StartTracerOption {
+	return func(tracer *Tracer) {
+		tracer.exportTimeout = d
+	}
+}
+
+func WithExporter(exporter Exporter) StartTracerOption {
+	return func(tracer *Tracer) {
+		tracer.exporter = exporter
+	}
+}
+
+// WithPropagator sets the Propagator used by Inject/Extract for the
+// opentracing.HTTPHeaders and opentracing.TextMap formats, W3CPropagator is
+// used by default.
+func WithPropagator(propagator Propagator) StartTracerOption {
+	return func(tracer *Tracer) {
+		tracer.propagator = propagator
+	}
+}
+
+// WithPropagators combines propagators into a CompositePropagator, Extract
+// tries them in the given order and Inject writes all of them.
+func WithPropagators(propagators ...Propagator) StartTracerOption {
+	return func(tracer *Tracer) {
+		tracer.propagator = CompositePropagator(propagators)
+	}
+}
+
 func NewTracer(service string, opts ...StartTracerOption) *Tracer {
 	envs := getEnvPairs()
 	if s, ok := envs[ServiceNameKey]; ok {
@@ -78,8 +126,32 @@ func NewTracer(service string, opts ...StartTracerOption) *Tracer {
 	if tracer.flushInterval <= 0 {
 		tracer.flushInterval = DefFlushInterval
 	}
-	tracer.flush = make(chan struct{})
+	if tracer.exportTimeout <= 0 {
+		tracer.exportTimeout = DefExportTimeout
+	}
+	if tracer.exporter == nil {
+		tracer.exporter = NoopExporter{}
+	}
+	if exporter, ok := tracer.exporter.(globalTagsExporter); ok {
+		exporter.setGlobalTags(tracer.tags)
+	}
+	if tracer.tailSampling != nil {
+		tracer.exporter = NewTailSampler(tracer.exporter, *tracer.tailSampling)
+	}
+	if tracer.idGenerator == nil {
+		tracer.idGenerator = NewRandomIDGenerator()
+	}
+	if tracer.propagator == nil {
+		if names, ok := envs[PropagationKey]; ok {
+			tracer.propagator = ParsePropagators(names)
+		}
+	}
+	if tracer.propagator == nil {
+		tracer.propagator = W3CPropagator{}
+	}
+	tracer.flush = make(chan struct{}, 1)
 	tracer.close = make(chan struct{})
+	tracer.done = make(chan struct{})
 
 	if tracer.sampler == nil {
 		if p, ok := envs[SampleRatioKey]; ok {
@@ -93,13 +165,24 @@ func NewTracer(service string, opts ...StartTracerOption) *Tracer {
 }
 
 type Tracer struct {
-	service       string
-	sampler       Sampler
-	tags          map[string]interface{}
-	finished      chan *Span
-	flush         chan struct{}
-	flushInterval time.Duration
-	close         chan struct{}
+	service         string
+	sampler         Sampler
+	tags            map[string]interface{}
+	finished        chan *Span
+	flush           chan struct{}
+	flushInterval   time.Duration
+	exportTimeout   time.Duration
+	close           chan struct{}
+	started         int32
+	done            chan struct{}
+	exporter        Exporter
+	propagator      Propagator
+	baggageMaxItems int
+	baggageMaxBytes int
+	idGenerator     IDGenerator
+	traceID128      bool
+	tailSampling    *TailSamplingConfig
+	forced          sync.Map
 }
 
 // Create, start, and return a new Span with the given `operationName` and
@@ -143,46 +226,125 @@ func (tcr *Tracer) StartSpan(operationName string, opts ...opentracing.StartSpan
 		start = ssopts.StartTime.UnixNano()
 	}
 
-	var span = &Span{SpanID: newID(start)}
-	if len(ssopts.References) > 0 {
-		spctx := ssopts.References[0].ReferencedContext.(*SpanContext)
-		span.TraceID = spctx.TraceID
-		span.ParentID = spctx.ParentID
-		for k, v := range spctx.Meta {
-			span.SetTag(k, v)
-		}
-	}
+	spctx, remote, links := parentFromReferences(ssopts.References)
 
 	sp := &Span{
 		Service:   tcr.service,
+		Operation: operationName,
 		StartTime: start,
+		Links:     links,
 	}
 	sp.SetTags(tcr.tags)
 	sp.SetTags(ssopts.Tags)
+	// only decisions made on this span are recorded for the trace, decisions
+	// inherited from parent are not
+	forced := isUserPriority(sp.samplePriority())
 
 	if spctx != nil {
+		sp.TraceIDHigh = spctx.TraceIDHigh
 		sp.TraceID = spctx.TraceID
 		sp.ParentID = spctx.ParentID
-		for k, v := range spctx.Meta {
-			sp.SetTag(k, v)
+		sp.TraceState = spctx.TraceState
+		if len(spctx.Meta) != 0 {
+			maxItems, maxBytes := tcr.baggageLimits()
+			sp.Baggage = make(map[string]string, len(spctx.Meta))
+			for k, v := range spctx.Meta {
+				setBaggageItem(sp.Baggage, k, v, maxItems, maxBytes)
+			}
 		}
-		if sp.ParentID == 0 {
-			sp.SetTag(SamplePriorityKey, &Numeric_Int32Value{Int32Value: int32(spctx.SamplePriority)})
-			sp.SetTag(SampleRatioKey, &Numeric_Doublevalue{Doublevalue: spctx.SampleRatio})
+		if !forced {
+			tcr.sampleChild(sp, spctx, remote)
 		}
 	} else {
-		sp.TraceID = newID(sp.StartTime)
-		sp.ParentID = 0
-		if tcr.sampler != nil {
-			sp.SetTag(SamplePriorityKey, &Numeric_Int32Value{Int32Value: int32(SamplePriority_AutoKeep)})
-			sp.SetTag(SampleRatioKey, &Numeric_Doublevalue{Doublevalue: tcr.sampler.Ratio()})
+		high, low := tcr.idGenerator.NewTraceID()
+		if tcr.traceID128 {
+			sp.TraceIDHigh = high
 		}
+		sp.TraceID = low
+		sp.ParentID = 0
+		tcr.sample(sp)
+	}
+	sp.SpanID = tcr.idGenerator.NewSpanID()
+	if forced {
+		tcr.forceSampling(sp, sp.samplePriority())
+	} else {
+		tcr.applyForcedSampling(sp)
 	}
-	sp.SpanID = newID(time.Now().UnixNano())
+	spanTracers.Store(sp, spanOwner{tracer: tcr, started: time.Now()})
 
 	return sp
 }
 
+// sample makes the sampling decision of root span with the trace id, spans
+// are kept if there is no Sampler or forced by user.
+func (tcr *Tracer) sample(span *Span) {
+	if tcr.sampler == nil || isUserPriority(span.samplePriority()) {
+		return
+	}
+
+	keep, ratio := sampleWith(tcr.sampler, span)
+	priority := SamplePriority_SamplerKeep
+	if !keep {
+		priority = SamplePriority_SamplerBlock
+	}
+	setSampling(span, priority, ratio)
+}
+
+// sampleChild makes the sampling decision of span started with parent, the
+// decision of parent is followed unless the Sampler is a ParentSampler and
+// the parent decision was not forced by user.
+func (tcr *Tracer) sampleChild(span *Span, parent *SpanContext, remote bool) {
+	ps, ok := tcr.sampler.(ParentSampler)
+	if !ok || isUserPriority(parent.SamplePriority) {
+		setSampling(span, parent.SamplePriority, parent.SampleRatio)
+
+		return
+	}
+
+	keep, ratio := ps.SampleChild(span, parent, remote)
+	switch {
+	case keep == isKeep(parent.SamplePriority):
+		setSampling(span, parent.SamplePriority, ratio)
+	case keep:
+		setSampling(span, SamplePriority_SamplerKeep, ratio)
+	default:
+		setSampling(span, SamplePriority_SamplerBlock, ratio)
+	}
+}
+
+// parentFromReferences records every reference to a SpanContext of this
+// package as a SpanLink and picks the parent, which is the first ChildOf
+// reference or, if there is none, the first FollowsFrom reference. References
+// to foreign SpanContext implementations are ignored.
+func parentFromReferences(refs []opentracing.SpanReference) (parent *SpanContext, remote bool, links []*SpanLink) {
+	var parentChildOf bool
+	for _, ref := range refs {
+		spctx, isRemote := spanContextOf(ref.ReferencedContext)
+		if spctx == nil || (spctx.TraceIDHigh == 0 && spctx.TraceID == 0) {
+			continue
+		}
+
+		link := &SpanLink{TraceIDHigh: spctx.TraceIDHigh, TraceID: spctx.TraceID, SpanID: spctx.ParentID}
+		switch ref.Type {
+		case opentracing.ChildOfRef:
+			link.Type = SpanReferenceType_ChildOf
+			if !parentChildOf {
+				parent, remote, parentChildOf = spctx, isRemote, true
+			}
+		case opentracing.FollowsFromRef:
+			link.Type = SpanReferenceType_FollowsFrom
+			if parent == nil {
+				parent, remote = spctx, isRemote
+			}
+		default:
+			continue
+		}
+		links = append(links, link)
+	}
+
+	return parent, remote, links
+}
+
 // Inject() takes the `sm` SpanContext instance and injects it for
 // propagation within `carrier`. The actual type of `carrier` depends on
 // the value of `format`.
@@ -213,7 +375,33 @@ func (tcr *Tracer) StartSpan(operationName string, opts ...opentracing.StartSpan
 //
 // See Tracer.Extract().
 func (tcr *Tracer) Inject(sm opentracing.SpanContext, format interface{}, carrier interface{}) error {
-	return nil
+	spctx, _ := spanContextOf(sm)
+	if spctx == nil {
+		return opentracing.ErrInvalidSpanContext
+	}
+	if fd, ok := tcr.forced.Load(traceKey{high: spctx.TraceIDHigh, low: spctx.TraceID}); ok {
+		spctx = proto.Clone(spctx).(*SpanContext)
+		spctx.SamplePriority = fd.(forcedDecision).priority
+	}
+
+	switch format {
+	case opentracing.HTTPHeaders, opentracing.TextMap:
+		writer, ok := carrier.(opentracing.TextMapWriter)
+		if !ok {
+			return opentracing.ErrInvalidCarrier
+		}
+
+		return tcr.propagator.Inject(spctx, writer)
+	case opentracing.Binary:
+		writer, ok := carrier.(io.Writer)
+		if !ok {
+			return opentracing.ErrInvalidCarrier
+		}
+
+		return injectBinary(spctx, writer)
+	default:
+		return opentracing.ErrUnsupportedFormat
+	}
 }
 
 // Extract() returns a SpanContext instance given `format` and `carrier`.
@@ -258,12 +446,46 @@ func (tcr *Tracer) Inject(sm opentracing.SpanContext, format interface{}, carrie
 //
 // See Tracer.Inject().
 func (tcr *Tracer) Extract(format interface{}, carrier interface{}) (opentracing.SpanContext, error) {
-	return nil, nil
+	switch format {
+	case opentracing.HTTPHeaders, opentracing.TextMap:
+		reader, ok := carrier.(opentracing.TextMapReader)
+		if !ok {
+			return nil, opentracing.ErrInvalidCarrier
+		}
+
+		spctx, err := tcr.propagator.Extract(reader)
+		if err != nil {
+			return nil, err
+		}
+
+		return &remoteSpanContext{SpanContext: spctx}, nil
+	case opentracing.Binary:
+		reader, ok := carrier.(io.Reader)
+		if !ok {
+			return nil, opentracing.ErrInvalidCarrier
+		}
+
+		spctx, err := extractBinary(reader)
+		if err != nil {
+			return nil, err
+		}
+
+		return &remoteSpanContext{SpanContext: spctx}, nil
+	default:
+		return nil, opentracing.ErrUnsupportedFormat
+	}
 }
 
 func (tcr *Tracer) Start() {
+	if !atomic.CompareAndSwapInt32(&tcr.started, 0, 1) {
+		return
+	}
+
 	go func() {
+		defer close(tcr.done)
+
 		ticker := time.NewTicker(tcr.flushInterval)
+		defer ticker.Stop()
 		for {
 			select {
 			case <-tcr.close:
@@ -287,44 +509,111 @@ func (tcr *Tracer) Start() {
 	}()
 }
 
+// Flush asks the flush loop to export the finished spans, it does not wait
+// for the export and a request already pending is not repeated.
 func (tcr *Tracer) Flush() {
-	tcr.flush <- struct{}{}
+	select {
+	case tcr.flush <- struct{}{}:
+	default:
+	}
 }
 
+// Close stops the flush loop and waits for its running export, closes the
+// Sampler if it polls in background, then exports the spans still buffered
+// and shuts down the Exporter.
 func (tcr *Tracer) Close() {
 	select {
 	case <-tcr.close:
+		return
 	default:
 		close(tcr.close)
 	}
+	if atomic.LoadInt32(&tcr.started) != 0 {
+		<-tcr.done
+	}
+
+	closeSampler(tcr.sampler)
+	if err := tcr.doFlush(); err != nil {
+		fmt.Println(err.Error())
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), tcr.exportTimeout)
+	defer cancel()
+	if err := tcr.exporter.Shutdown(ctx); err != nil {
+		fmt.Println(err.Error())
+	}
 }
 
 func (tcr *Tracer) finishSpan(span *Span) error {
+	tcr.applyForcedSampling(span)
+	// spans blocked by Sampler are left to the tail policies if any, only
+	// user decisions are final
+	if p := span.samplePriority(); p == SamplePriority_UserBlock || (!isKeep(p) && tcr.tailSampling == nil) {
+		return nil
+	}
+
+	select {
+	case <-tcr.close:
+		return errTracerClosed
+	default:
+	}
+	select {
+	case tcr.finished <- span:
+		return nil
+	default:
+	}
+	// the buffer is full, without flush loop nothing will drain it
+	if atomic.LoadInt32(&tcr.started) == 0 {
+		return errFinishedBufferFull
+	}
+
+	tcr.Flush()
 	timeout := time.NewTimer(time.Second)
-	for {
-		select {
-		case tcr.finished <- span:
-			return nil
-		case <-timeout.C:
-			return errors.New("finish span timeout")
-		default:
-			tcr.Flush()
-		}
+	defer timeout.Stop()
+	select {
+	case <-tcr.close:
+		return errTracerClosed
+	case tcr.finished <- span:
+		return nil
+	case <-timeout.C:
+		return errors.New("finish span timeout")
 	}
 }
 
+// expireSpans forgets the unfinished spans started before deadline, spans of
+// closed tracers included.
+func (tcr *Tracer) expireSpans(deadline time.Time) {
+	spanTracers.Range(func(key, value interface{}) bool {
+		if value.(spanOwner).started.Before(deadline) {
+			spanTracers.Delete(key)
+		}
+
+		return true
+	})
+}
+
 func (tcr *Tracer) doFlush() error {
+	now := time.Now()
+	tcr.expireForcedSampling(now)
+	tcr.expireSpans(now.Add(-DefMaxSpanDuration))
+
 	l := len(tcr.finished)
-	if l == 0 {
+	trace := &Trace{Trace: make([]*Span, 0, l)}
+	for i := 0; i < l; i++ {
+		select {
+		case span := <-tcr.finished:
+			trace.Trace = append(trace.Trace, span)
+		default:
+		}
+	}
+	// TailSampler decides the traces of elapsed windows on every flush
+	if len(trace.Trace) == 0 && tcr.tailSampling == nil {
 		return nil
 	}
 
-	trace := &Trace{Trace: make([]*Span, l)}
-	for i := 0; i < l; i++ {
-		trace.Trace[i] = <-tcr.finished
-	}
+	ctx, cancel := context.WithTimeout(context.Background(), tcr.exportTimeout)
+	defer cancel()
 
-	return tcr.endpoint.Export(trace)
+	return tcr.exporter.Export(ctx, trace)
 }
 
 func getEnvPairs() map[string]string {
@@ -340,13 +629,3 @@ func getEnvPairs() map[string]string {
 
 	return m
 }
-
-func newID(start int64) int64 {
-	return rand.Int63() ^ start
-}
-
-func init() {
-	// log.SetOutput(os.Stdout)
-	// log.SetFlags(log.LstdFlags | log.Lshortfile)
-	rand.Seed(time.Now().UnixNano())
-}
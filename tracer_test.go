@@ -0,0 +1,361 @@
+package optcgo
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/log"
+	"github.com/opentracing/opentracing-go/mocktracer"
+)
+
+type recordExporter struct {
+	sync.Mutex
+	spans    []*Span
+	shutdown bool
+}
+
+func (rexp *recordExporter) Export(ctx context.Context, trace *Trace) error {
+	rexp.Lock()
+	defer rexp.Unlock()
+
+	rexp.spans = append(rexp.spans, trace.Trace...)
+
+	return nil
+}
+
+func (rexp *recordExporter) Shutdown(ctx context.Context) error {
+	rexp.Lock()
+	defer rexp.Unlock()
+
+	rexp.shutdown = true
+
+	return nil
+}
+
+func (rexp *recordExporter) Spans() []*Span {
+	rexp.Lock()
+	defer rexp.Unlock()
+
+	return rexp.spans
+}
+
+func startTestTracer(t *testing.T, opts ...StartTracerOption) (*Tracer, *recordExporter) {
+	t.Helper()
+
+	exporter := &recordExporter{}
+	tracer := NewTracer("test_service", append([]StartTracerOption{WithExporter(exporter)}, opts...)...)
+	tracer.Start()
+	opentracing.SetGlobalTracer(tracer)
+	t.Cleanup(func() {
+		tracer.Close()
+		opentracing.SetGlobalTracer(opentracing.NoopTracer{})
+	})
+
+	return tracer, exporter
+}
+
+func TestTracerExport(t *testing.T) {
+	tracer, exporter := startTestTracer(t)
+
+	root := tracer.StartSpan("root")
+	child := tracer.StartSpan("child", opentracing.ChildOf(root.Context()))
+	child.Finish()
+	root.Finish()
+	tracer.Close()
+
+	spans := exporter.Spans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 exported spans, got %d", len(spans))
+	}
+	if spans[0].TraceID != spans[1].TraceID {
+		t.Errorf("child and root are in different traces: %d != %d", spans[0].TraceID, spans[1].TraceID)
+	}
+	if spans[0].ParentID != spans[1].SpanID {
+		t.Errorf("child parent id %d does not match root span id %d", spans[0].ParentID, spans[1].SpanID)
+	}
+	if !exporter.shutdown {
+		t.Error("exporter not shut down on Close")
+	}
+}
+
+func TestTracerExportWithoutGlobalTracer(t *testing.T) {
+	exporter := &recordExporter{}
+	tracer := NewTracer("test_service", WithExporter(exporter), WithBaggageLimits(1, 0))
+	tracer.Start()
+
+	span := tracer.StartSpan("root")
+	span.SetBaggageItem("k1", "v1").SetBaggageItem("k2", "v2")
+	if span.Tracer() != tracer {
+		t.Error("span does not refer to the tracer that started it")
+	}
+	if span.BaggageItem("k2") != "" {
+		t.Error("baggage limits of the tracer were not applied")
+	}
+	span.Finish()
+	tracer.Close()
+
+	if spans := exporter.Spans(); len(spans) != 1 {
+		t.Fatalf("expected 1 exported span, got %d", len(spans))
+	}
+}
+
+type slowExporter struct {
+	recordExporter
+	exporting int32
+	overlap   int32
+}
+
+func (sexp *slowExporter) Export(ctx context.Context, trace *Trace) error {
+	atomic.StoreInt32(&sexp.exporting, 1)
+	defer atomic.StoreInt32(&sexp.exporting, 0)
+	time.Sleep(50 * time.Millisecond)
+
+	return sexp.recordExporter.Export(ctx, trace)
+}
+
+func (sexp *slowExporter) Shutdown(ctx context.Context) error {
+	if atomic.LoadInt32(&sexp.exporting) != 0 {
+		atomic.StoreInt32(&sexp.overlap, 1)
+	}
+
+	return sexp.recordExporter.Shutdown(ctx)
+}
+
+func TestTracerCloseWaitsForExport(t *testing.T) {
+	exporter := &slowExporter{}
+	tracer := NewTracer("test_service", WithExporter(exporter))
+	tracer.Start()
+
+	tracer.StartSpan("root").Finish()
+	tracer.Flush()
+	tracer.Close()
+
+	if atomic.LoadInt32(&exporter.overlap) != 0 {
+		t.Error("exporter shut down while exporting")
+	}
+	if spans := exporter.Spans(); len(spans) != 1 {
+		t.Errorf("expected 1 exported span, got %d", len(spans))
+	}
+}
+
+type blockingExporter struct {
+	recordExporter
+	deadline int32
+}
+
+func (bexp *blockingExporter) Export(ctx context.Context, trace *Trace) error {
+	if _, ok := ctx.Deadline(); ok {
+		atomic.StoreInt32(&bexp.deadline, 1)
+	}
+	<-ctx.Done()
+
+	return ctx.Err()
+}
+
+func TestTracerExportTimeout(t *testing.T) {
+	exporter := &blockingExporter{}
+	tracer := NewTracer("test_service", WithExporter(exporter), WithExportTimeout(20*time.Millisecond))
+	tracer.Start()
+
+	tracer.StartSpan("root").Finish()
+	start := time.Now()
+	tracer.Close()
+
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("Close waited %s for a blocked exporter", d)
+	}
+	if atomic.LoadInt32(&exporter.deadline) == 0 {
+		t.Error("export context has no deadline")
+	}
+}
+
+func finishWithin(t *testing.T, span opentracing.Span, d time.Duration) {
+	t.Helper()
+
+	done := make(chan struct{})
+	go func() {
+		span.Finish()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(d):
+		t.Fatalf("Finish did not return within %s", d)
+	}
+}
+
+func TestFinishWithoutStart(t *testing.T) {
+	exporter := &recordExporter{}
+	tracer := NewTracer("test_service", WithExporter(exporter), WithFlushBuffer(1))
+
+	finishWithin(t, tracer.StartSpan("first"), 100*time.Millisecond)
+	finishWithin(t, tracer.StartSpan("second"), 100*time.Millisecond)
+	tracer.Flush()
+	tracer.Close()
+
+	if spans := exporter.Spans(); len(spans) != 1 || spans[0].Operation != "first" {
+		t.Errorf("expected only the first span exported, got %v", spans)
+	}
+}
+
+func TestFinishAfterClose(t *testing.T) {
+	tracer, exporter := startTestTracer(t)
+
+	span := tracer.StartSpan("late")
+	tracer.Close()
+	finishWithin(t, span, 100*time.Millisecond)
+
+	if spans := exporter.Spans(); len(spans) != 0 {
+		t.Errorf("expected no exported span, got %d", len(spans))
+	}
+}
+
+func TestFinishTwice(t *testing.T) {
+	tracer, exporter := startTestTracer(t)
+
+	span := tracer.StartSpan("root").(*Span)
+	span.Finish()
+	end := span.EndTime
+	span.Finish()
+	tracer.Close()
+
+	if span.EndTime != end {
+		t.Errorf("second Finish changed end time from %d to %d", end, span.EndTime)
+	}
+	if spans := exporter.Spans(); len(spans) != 1 {
+		t.Errorf("expected 1 exported span, got %d", len(spans))
+	}
+}
+
+func TestExpireSpans(t *testing.T) {
+	tracer := NewTracer("test_service")
+	defer tracer.Close()
+
+	span := tracer.StartSpan("root").(*Span)
+	if span.Tracer() != tracer {
+		t.Fatal("span not tracked by its tracer")
+	}
+	tracer.expireSpans(time.Now().Add(time.Second))
+	if _, ok := spanTracers.Load(span); ok {
+		t.Error("expected span to be expired")
+	}
+}
+
+func TestBaggage(t *testing.T) {
+	tracer, _ := startTestTracer(t, WithBaggageLimits(2, 32))
+
+	root := tracer.StartSpan("root")
+	root.SetBaggageItem("user", "alice")
+	root.SetBaggageItem("tenant", "acme, inc")
+	root.SetBaggageItem("dropped", "exceeds item limit")
+	if v := root.BaggageItem("dropped"); v != "" {
+		t.Errorf("baggage item over limit kept: %q", v)
+	}
+
+	header := http.Header{}
+	if err := tracer.Inject(root.Context(), opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(header)); err != nil {
+		t.Fatal(err.Error())
+	}
+	remote, err := tracer.Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(header))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	child := tracer.StartSpan("child", opentracing.ChildOf(remote))
+	if v := child.BaggageItem("user"); v != "alice" {
+		t.Errorf("expected inherited baggage user=alice, got %q", v)
+	}
+	if v := child.BaggageItem("tenant"); v != "acme, inc" {
+		t.Errorf("expected inherited baggage tenant=acme, inc, got %q", v)
+	}
+	child.SetBaggageItem("user", "a value long enough to exceed the byte limit")
+	if v := child.BaggageItem("user"); v != "alice" {
+		t.Errorf("baggage item over byte limit replaced: %q", v)
+	}
+}
+
+func TestFinishWithOptions(t *testing.T) {
+	tracer, exporter := startTestTracer(t)
+
+	start := time.Now().Add(-time.Minute)
+	finish := start.Add(time.Second)
+	sp := tracer.StartSpan("batch", opentracing.StartTime(start))
+	sp.FinishWithOptions(opentracing.FinishOptions{
+		FinishTime: finish,
+		LogRecords: []opentracing.LogRecord{
+			{Timestamp: start.Add(time.Millisecond), Fields: []log.Field{log.String("event", "read")}},
+		},
+		BulkLogData: []opentracing.LogData{
+			{Timestamp: start.Add(2 * time.Millisecond), Event: "write"},
+		},
+	})
+	tracer.Close()
+
+	spans := exporter.Spans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 exported span, got %d", len(spans))
+	}
+	if spans[0].EndTime != finish.UnixNano() {
+		t.Errorf("expected end time %d, got %d", finish.UnixNano(), spans[0].EndTime)
+	}
+	if len(spans[0].Logs) != 2 {
+		t.Fatalf("expected 2 logs, got %d", len(spans[0].Logs))
+	}
+	if ts := spans[0].Logs[1].Timestamp; ts != start.Add(2*time.Millisecond).UnixNano() {
+		t.Errorf("unexpected bulk log timestamp %d", ts)
+	}
+}
+
+func TestSetOperationName(t *testing.T) {
+	tracer, exporter := startTestTracer(t)
+
+	sp := tracer.StartSpan("http.request")
+	if op := sp.(*Span).Operation; op != "http.request" {
+		t.Errorf("expected operation http.request, got %q", op)
+	}
+	sp.SetOperationName("GET /users/:id")
+	sp.Finish()
+	tracer.Close()
+
+	spans := exporter.Spans()
+	if len(spans) != 1 || spans[0].Operation != "GET /users/:id" {
+		t.Fatalf("expected renamed span to be exported, got %v", spans)
+	}
+}
+
+func TestStartSpanReferences(t *testing.T) {
+	tracer, _ := startTestTracer(t)
+
+	producer1 := tracer.StartSpan("produce")
+	producer2 := tracer.StartSpan("produce")
+	parent := tracer.StartSpan("poll")
+	consumer := tracer.StartSpan("consume",
+		opentracing.FollowsFrom(producer1.Context()),
+		opentracing.FollowsFrom(producer2.Context()),
+		opentracing.ChildOf(parent.Context()),
+		opentracing.ChildOf(mocktracer.MockSpanContext{TraceID: 1, SpanID: 2}),
+	).(*Span)
+
+	if consumer.TraceID != parent.(*Span).TraceID || consumer.ParentID != parent.(*Span).SpanID {
+		t.Errorf("expected ChildOf reference as parent, got trace %d parent %d", consumer.TraceID, consumer.ParentID)
+	}
+	if len(consumer.Links) != 3 {
+		t.Fatalf("expected 3 links, got %d", len(consumer.Links))
+	}
+	if l := consumer.Links[1]; l.Type != SpanReferenceType_FollowsFrom || l.SpanID != producer2.(*Span).SpanID {
+		t.Errorf("unexpected FollowsFrom link %v", l)
+	}
+	if l := consumer.Links[2]; l.Type != SpanReferenceType_ChildOf || l.SpanID != parent.(*Span).SpanID {
+		t.Errorf("unexpected ChildOf link %v", l)
+	}
+
+	follower := tracer.StartSpan("follow", opentracing.FollowsFrom(producer1.Context())).(*Span)
+	if follower.ParentID != producer1.(*Span).SpanID {
+		t.Errorf("expected FollowsFrom reference as parent without ChildOf, got %d", follower.ParentID)
+	}
+}
@@ -2,8 +2,8 @@ package optcgo
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"os"
 )
@@ -29,7 +29,7 @@ type Decoder func(body io.Reader) SpanNative
 
 type SpanTransform interface {
 	RegisterDecoder(contentType string, decoder Decoder)
-	BuildSpanFrom(native SpanNative) Span
+	BuildSpanFrom(native SpanNative) *Span
 }
 
 type SpanTransformServerConfig struct {
@@ -38,16 +38,26 @@ type SpanTransformServerConfig struct {
 }
 
 func (svr *SpanTransformServerConfig) newSpanTransformServer() *SpanTransformServer {
-
+	return &SpanTransformServer{mux: http.NewServeMux()}
 }
 
 type SpanTransformServer struct {
 	mux *http.ServeMux
 }
 
-func (sts *SpanTransformServer) BuildSpanFrom(native SpanNative) Span {
-	svr := http.NewServeMux()
-	http.ListenAndServe("", svr)
+func (sts *SpanTransformServer) BuildSpanFrom(native SpanNative) *Span {
+	return &Span{
+		TraceID:   native.GetTraceID(),
+		ParentID:  native.GetParentID(),
+		SpanID:    native.GetSpanID(),
+		Service:   native.GetService(),
+		Operation: native.GetOperation(),
+		Meta:      native.GetMeta(),
+		Metrics:   native.GetMetrics(),
+		Status:    native.GetSpanStatus(),
+		StartTime: native.GetStartTime(),
+		EndTime:   native.GetEndTime(),
+	}
 }
 
 const (
@@ -55,24 +65,25 @@ const (
 	address     = "OTTF_ADDRESS"
 )
 
-func init() {
-	log.SetOutput(os.Stdout)
-	log.SetFlags(log.LstdFlags | log.Lshortfile)
-
-	v, ok := os.LookupEnv(config_path)
-	if !ok {
-		v = "./config.json"
-	}
-	if _, err := os.Stat(v); os.IsNotExist(err) {
-		log.Fatalln("config file not exists")
+// LoadSpanTransformServerConfig reads the json config at path, an empty path
+// falls back to $OTTF_CONFIG_PATH and then to ./config.json.
+func LoadSpanTransformServerConfig(path string) (*SpanTransformServerConfig, error) {
+	if path == "" {
+		if v, ok := os.LookupEnv(config_path); ok {
+			path = v
+		} else {
+			path = "./config.json"
+		}
 	}
 
+	bts, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
 	config := &SpanTransformServerConfig{}
-	if bts, err := os.ReadFile(v); err != nil {
-		log.Fatalln(err.Error())
-	} else {
-		if err = json.Unmarshal(bts, config); err != nil {
-			log.Println("load config file failed with error: %s", err.Error())
-		}
+	if err = json.Unmarshal(bts, config); err != nil {
+		return nil, fmt.Errorf("load config file failed with error: %w", err)
 	}
+
+	return config, nil
 }
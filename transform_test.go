@@ -2,6 +2,8 @@ package optcgo
 
 import (
 	"log"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -18,3 +20,22 @@ func TestOpenTracing(t *testing.T) {
 
 	log.Printf("%#v", span)
 }
+
+func TestLoadSpanTransformServerConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"host":"localhost","port":9529}`), 0o644); err != nil {
+		t.Fatal(err.Error())
+	}
+	t.Setenv(config_path, path)
+
+	config, err := LoadSpanTransformServerConfig("")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if config.Host != "localhost" || config.Port != 9529 {
+		t.Errorf("unexpected config %v", config)
+	}
+	if _, err = LoadSpanTransformServerConfig(path + ".missing"); err == nil {
+		t.Error("expected error for missing config file")
+	}
+}